@@ -22,6 +22,19 @@ type Compare struct {
 	term          bool
 	interactive   bool
 	maxLines      int
+	markers       string
+	format        string
+	context       int
+	maxDiffLines  int
+	tabWidth      int
+	width         int
+	overflow      string
+	lineNumbers   bool
+	tmpl          string
+	color         string
+	theme         string
+	stat          bool
+	layout        string
 	SubCommands   map[string]Cmd
 	CommandAction func(c *Compare) error
 }
@@ -107,6 +120,165 @@ func (c *Compare) Execute(args []string) error {
 					return fmt.Errorf("invalid integer value for flag %s: %s", name, value)
 				}
 				c.maxLines = iv
+
+			case "markers":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				c.markers = value
+
+			case "format", "f":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				c.format = value
+
+			case "context", "C":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				iv, err := strconv.Atoi(value)
+				if err != nil {
+					return fmt.Errorf("invalid integer value for flag %s: %s", name, value)
+				}
+				c.context = iv
+
+			case "maxDiffLines", "max-diff-lines":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				iv, err := strconv.Atoi(value)
+				if err != nil {
+					return fmt.Errorf("invalid integer value for flag %s: %s", name, value)
+				}
+				c.maxDiffLines = iv
+
+			case "tabWidth", "tab-width":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				iv, err := strconv.Atoi(value)
+				if err != nil {
+					return fmt.Errorf("invalid integer value for flag %s: %s", name, value)
+				}
+				c.tabWidth = iv
+
+			case "width", "w":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				iv, err := strconv.Atoi(value)
+				if err != nil {
+					return fmt.Errorf("invalid integer value for flag %s: %s", name, value)
+				}
+				c.width = iv
+
+			case "overflow":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				c.overflow = value
+
+			case "lineNumbers", "line-numbers", "n":
+				if hasValue {
+					b, err := strconv.ParseBool(value)
+					if err != nil {
+						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+					}
+					c.lineNumbers = b
+				} else {
+					c.lineNumbers = true
+				}
+
+			case "template":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				c.tmpl = value
+
+			case "color":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				c.color = value
+
+			case "theme":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				c.theme = value
+
+			case "stat":
+				if hasValue {
+					b, err := strconv.ParseBool(value)
+					if err != nil {
+						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+					}
+					c.stat = b
+				} else {
+					c.stat = true
+				}
+
+			case "layout":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				c.layout = value
 			case "help", "h":
 				c.Usage()
 				return nil
@@ -164,11 +336,41 @@ func (c *RootCmd) NewCompare() *Compare {
 
 	set.IntVar(&v.maxLines, "max-lines", 1000, "Max lines to search for alignment")
 	set.IntVar(&v.maxLines, "m", 1000, "Max lines to search for alignment")
+
+	set.StringVar(&v.markers, "markers", "", "Plain-text change markers (caret, inline)")
+
+	set.StringVar(&v.format, "format", "", "Output format (side-by-side, unified, context, normal, ed, json, html, markdown, stat, word-diff, junit, github, quickfix)")
+	set.StringVar(&v.format, "f", "", "Output format (side-by-side, unified, context, normal, ed, json, html, markdown, stat, word-diff, junit, github, quickfix)")
+
+	set.IntVar(&v.context, "context", -1, "Lines of context around changes (-1 for the format default)")
+	set.IntVar(&v.context, "C", -1, "Lines of context around changes (-1 for the format default)")
+
+	set.IntVar(&v.maxDiffLines, "max-diff-lines", 0, "Max diff lines per file in Markdown output (0 for no limit)")
+
+	set.IntVar(&v.tabWidth, "tab-width", 8, "Columns between tab stops when expanding tabs")
+
+	set.IntVar(&v.width, "width", 0, "Output width in columns (0 for no limit, -1 to fit the terminal)")
+	set.IntVar(&v.width, "w", 0, "Output width in columns (0 for no limit, -1 to fit the terminal)")
+
+	set.StringVar(&v.overflow, "overflow", "", "What to do with lines wider than their column (wrap, truncate)")
+
+	set.BoolVar(&v.lineNumbers, "line-numbers", false, "Show line numbers in side-by-side output")
+	set.BoolVar(&v.lineNumbers, "n", false, "Show line numbers in side-by-side output")
+
+	set.StringVar(&v.tmpl, "template", "", "Render with a text/template file or built-in template (changes, summary, xml)")
+
+	set.StringVar(&v.color, "color", "", "When to use colours (auto, always, never; by default only with --term)")
+
+	set.StringVar(&v.theme, "theme", "", "Colour theme (default, dark, light, colorblind) and element=colour overrides")
+
+	set.BoolVar(&v.stat, "stat", false, "Summarise the changed files; alone, or before the diff with --format or --template")
+
+	set.StringVar(&v.layout, "layout", "", "Side-by-side layout (columns, stacked, auto; auto stacks when too narrow for columns)")
 	set.Usage = v.Usage
 
 	v.CommandAction = func(c *Compare) error {
 
-		app.CompareFiles(c.file1, c.file2, c.term, c.interactive, c.maxLines)
+		app.CompareFiles(c.file1, c.file2, c.term, c.interactive, c.maxLines, c.markers, c.format, c.context, c.maxDiffLines, c.tabWidth, c.width, c.overflow, c.lineNumbers, c.tmpl, c.color, c.theme, c.stat, c.layout)
 		return nil
 	}
 
@@ -28,6 +28,30 @@ func TestCompare_Execute(t *testing.T) {
 	args = append(args, "--interactive")
 	args = append(args, "--maxLines")
 	args = append(args, "1")
+	args = append(args, "--markers")
+	args = append(args, "test")
+	args = append(args, "--format")
+	args = append(args, "test")
+	args = append(args, "--context")
+	args = append(args, "1")
+	args = append(args, "--max-diff-lines")
+	args = append(args, "1")
+	args = append(args, "--tab-width")
+	args = append(args, "1")
+	args = append(args, "--width")
+	args = append(args, "1")
+	args = append(args, "--overflow")
+	args = append(args, "test")
+	args = append(args, "--line-numbers")
+	args = append(args, "--template")
+	args = append(args, "test")
+	args = append(args, "--color")
+	args = append(args, "test")
+	args = append(args, "--theme")
+	args = append(args, "test")
+	args = append(args, "--stat")
+	args = append(args, "--layout")
+	args = append(args, "test")
 
 	err := cmd.Execute(args)
 	if err != nil {
@@ -52,4 +76,43 @@ func TestCompare_Execute(t *testing.T) {
 	if cmd.maxLines != 1 {
 		t.Errorf("Expected maxLines to be 1, got '%v'", cmd.maxLines)
 	}
+	if cmd.markers != "test" {
+		t.Errorf("Expected markers to be 'test', got '%v'", cmd.markers)
+	}
+	if cmd.format != "test" {
+		t.Errorf("Expected format to be 'test', got '%v'", cmd.format)
+	}
+	if cmd.context != 1 {
+		t.Errorf("Expected context to be 1, got '%v'", cmd.context)
+	}
+	if cmd.maxDiffLines != 1 {
+		t.Errorf("Expected maxDiffLines to be 1, got '%v'", cmd.maxDiffLines)
+	}
+	if cmd.tabWidth != 1 {
+		t.Errorf("Expected tabWidth to be 1, got '%v'", cmd.tabWidth)
+	}
+	if cmd.width != 1 {
+		t.Errorf("Expected width to be 1, got '%v'", cmd.width)
+	}
+	if cmd.overflow != "test" {
+		t.Errorf("Expected overflow to be 'test', got '%v'", cmd.overflow)
+	}
+	if cmd.lineNumbers != true {
+		t.Errorf("Expected lineNumbers to be true, got '%v'", cmd.lineNumbers)
+	}
+	if cmd.tmpl != "test" {
+		t.Errorf("Expected tmpl to be 'test', got '%v'", cmd.tmpl)
+	}
+	if cmd.color != "test" {
+		t.Errorf("Expected color to be 'test', got '%v'", cmd.color)
+	}
+	if cmd.theme != "test" {
+		t.Errorf("Expected theme to be 'test', got '%v'", cmd.theme)
+	}
+	if cmd.stat != true {
+		t.Errorf("Expected stat to be true, got '%v'", cmd.stat)
+	}
+	if cmd.layout != "test" {
+		t.Errorf("Expected layout to be 'test', got '%v'", cmd.layout)
+	}
 }
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// captureStdout runs f and returns what it wrote to standard output.
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+	f()
+	w.Close()
+	return <-out
+}
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDiffDirectories(t *testing.T) {
+	dir1 := writeTree(t, map[string]string{
+		"same.txt":    "same\n",
+		"sub/mod.txt": "a\nb\n",
+	})
+	dir2 := writeTree(t, map[string]string{
+		"same.txt":    "same\n",
+		"sub/mod.txt": "a\nc\n",
+		"new.txt":     "new\n",
+	})
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "stat",
+			args: []string{"--stat"},
+			want: " new.txt     | 1 +\n" +
+				" sub/mod.txt | 1 ~\n" +
+				" 2 files changed, 1 added, 0 removed, 1 modified\n",
+		},
+		{
+			name: "select file",
+			args: []string{"--stat", "-s", "mod.txt"},
+			want: " sub/mod.txt | 1 ~\n" +
+				" 1 file changed, 0 added, 0 removed, 1 modified\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := NewRoot("diff", "", "", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			args := append([]string{"diff", dir1, dir2}, tt.args...)
+			var runErr error
+			got := captureStdout(t, func() {
+				runErr = root.Execute(args)
+			})
+			if runErr != nil {
+				t.Fatalf("Execute(%q): %v", args, runErr)
+			}
+			if got != tt.want {
+				t.Errorf("diff %q:\ngot:\n%s\nwant:\n%s", tt.args, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package app
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"text/template"
 
 	"github.com/arran4/golang-diff/pkg/diff"
 )
@@ -20,7 +22,30 @@ import (
 //	term: --term -t Terminal mode (colors)
 //	interactive: --interactive -i Interactive mode
 //	maxLines: --max-lines -m (default: 1000) Max lines to search for alignment
-func CompareFiles(file1 string, file2 string, term bool, interactive bool, maxLines int) {
+//	markers: --markers Plain-text change markers (caret, inline)
+//	format: --format -f Output format (side-by-side, unified, context, normal, ed, json, html, markdown, stat, word-diff, junit, github, quickfix)
+//	context: --context -C (default: -1) Lines of context around changes (-1 for the format default)
+//	maxDiffLines: --max-diff-lines Max diff lines per file in Markdown output (0 for no limit)
+//	tabWidth: --tab-width (default: 8) Columns between tab stops when expanding tabs
+//	width: --width -w Output width in columns (0 for no limit, -1 to fit the terminal)
+//	overflow: --overflow What to do with lines wider than their column (wrap, truncate)
+//	lineNumbers: --line-numbers -n Show line numbers in side-by-side output
+//	tmpl: --template Render with a text/template file or built-in template (changes, summary, xml)
+//	color: --color When to use colours (auto, always, never; by default only with --term)
+//	theme: --theme Colour theme (default, dark, light, colorblind) and element=colour overrides
+//	stat: --stat Summarise the changed files; alone, or before the diff with --format or --template
+//	layout: --layout Side-by-side layout (columns, stacked, auto; auto stacks when too narrow for columns)
+func CompareFiles(file1 string, file2 string, term bool, interactive bool, maxLines int, markers string, format string, context int, maxDiffLines int, tabWidth int, width int, overflow string, lineNumbers bool, tmpl string, color string, theme string, stat bool, layout string) {
+	fi1, err := os.Stat(file1)
+	if err != nil {
+		fmt.Printf("Error reading %s: %v\n", file1, err)
+		return
+	}
+	fi2, err := os.Stat(file2)
+	if err != nil {
+		fmt.Printf("Error reading %s: %v\n", file2, err)
+		return
+	}
 	c1, err := os.ReadFile(file1)
 	if err != nil {
 		fmt.Printf("Error reading %s: %v\n", file1, err)
@@ -32,30 +57,79 @@ func CompareFiles(file1 string, file2 string, term bool, interactive bool, maxLi
 		return
 	}
 
+	if !validMarkers(markers) {
+		fmt.Printf("Unknown markers %q: expected caret or inline\n", markers)
+		return
+	}
+	if !validFormat(format) {
+		fmt.Printf("Unknown format %q: expected one of %s\n", format, formatNames())
+		return
+	}
+	if !validOverflow(overflow) {
+		fmt.Printf("Unknown overflow %q: expected wrap or truncate\n", overflow)
+		return
+	}
+	if !validLayout(layout) {
+		fmt.Printf("Unknown layout %q: expected auto, columns or stacked\n", layout)
+		return
+	}
+	if !validColor(color) {
+		fmt.Printf("Unknown color %q: expected auto, always or never\n", color)
+		return
+	}
+	colorTheme, err := diff.ParseTheme(theme)
+	if err != nil {
+		fmt.Printf("Invalid theme: %v\n", err)
+		return
+	}
+	showStat := false
+	if stat {
+		// --stat on its own prints just the summary.
+		if format == "" && tmpl == "" {
+			format = string(diff.OutputStat)
+		} else {
+			showStat = true
+		}
+	}
+
 	opts := []interface{}{
-		diff.TermMode(term),
+		diff.TermMode(useColor(color, term)),
+		colorTheme,
 		diff.Interactive(interactive),
 		diff.MaxLines(maxLines),
+		diff.Markers(markers),
+		diff.OutputFormat(format),
+		diff.MaxDiffLines(maxDiffLines),
+		diff.TabWidth(tabWidth),
+		diff.Width(outputWidth(width)),
+		diff.Overflow(overflow),
+		diff.Layout(layout),
+		diff.LineNumbers(lineNumbers),
+		diff.Stat(showStat),
+		diff.Labels{Left: file1, Right: file2},
+		diff.Timestamps{Left: fi1.ModTime(), Right: fi2.ModTime()},
 	}
-
-	output := diff.Compare(string(c1), string(c2), opts...)
-
-	if interactive {
-		// Use less for paging
-		cmd := exec.Command("less", "-R")
-		cmd.Stdin = strings.NewReader(output)
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		if err := cmd.Run(); err != nil {
-			// Fallback if less fails or not found
-			fmt.Print(output)
+	if context >= 0 {
+		opts = append(opts, diff.ContextLines(context), diff.Folding(true))
+	}
+	if tmpl != "" {
+		r, err := templateRenderer(tmpl)
+		if err != nil {
+			fmt.Printf("Error loading template: %v\n", err)
+			return
 		}
-	} else {
-		fmt.Print(output)
+		opts = append(opts, r)
+	}
+
+	err = printOutput(interactive, func(w io.Writer) error {
+		return diff.CompareTo(w, string(c1), string(c2), opts...)
+	})
+	if err != nil {
+		fmt.Printf("Error rendering output: %v\n", err)
 	}
 }
 
-// DiffFiles is a subcommand 'godiff diff'
+// DiffFiles is a subcommand `diff diff`
 // Compares two paths (files or directories) recursively.
 //
 // Flags:
@@ -66,11 +140,81 @@ func CompareFiles(file1 string, file2 string, term bool, interactive bool, maxLi
 //	interactive: --interactive -i Interactive mode
 //	maxLines: --max-lines -m (default: 1000) Max lines to search for alignment
 //	selectFile: --select-file -s Glob pattern to filter files
-func DiffFiles(path1, path2 string, term bool, interactive bool, maxLines int, selectFile string) {
+//	markers: --markers Plain-text change markers (caret, inline)
+//	format: --format -f Output format (side-by-side, unified, context, normal, ed, json, html, markdown, stat, word-diff, junit, github, quickfix)
+//	context: --context -C (default: -1) Lines of context around changes (-1 for the format default)
+//	maxDiffLines: --max-diff-lines Max diff lines per file in Markdown output (0 for no limit)
+//	tabWidth: --tab-width (default: 8) Columns between tab stops when expanding tabs
+//	width: --width -w Output width in columns (0 for no limit, -1 to fit the terminal)
+//	overflow: --overflow What to do with lines wider than their column (wrap, truncate)
+//	lineNumbers: --line-numbers -n Show line numbers in side-by-side output
+//	tmpl: --template Render with a text/template file or built-in template (changes, summary, xml)
+//	color: --color When to use colours (auto, always, never; by default only with --term)
+//	theme: --theme Colour theme (default, dark, light, colorblind) and element=colour overrides
+//	stat: --stat Summarise the changed files; alone, or before the diff with --format or --template
+//	layout: --layout Side-by-side layout (columns, stacked, auto; auto stacks when too narrow for columns)
+//	htmlDir: --html-dir Write an HTML report site to this directory instead of printing
+func DiffFiles(path1, path2 string, term bool, interactive bool, maxLines int, selectFile string, markers string, format string, context int, maxDiffLines int, tabWidth int, width int, overflow string, lineNumbers bool, tmpl string, color string, theme string, stat bool, layout string, htmlDir string) {
+	if !validMarkers(markers) {
+		fmt.Printf("Unknown markers %q: expected caret or inline\n", markers)
+		return
+	}
+	if !validFormat(format) {
+		fmt.Printf("Unknown format %q: expected one of %s\n", format, formatNames())
+		return
+	}
+	if !validOverflow(overflow) {
+		fmt.Printf("Unknown overflow %q: expected wrap or truncate\n", overflow)
+		return
+	}
+	if !validLayout(layout) {
+		fmt.Printf("Unknown layout %q: expected auto, columns or stacked\n", layout)
+		return
+	}
+	if !validColor(color) {
+		fmt.Printf("Unknown color %q: expected auto, always or never\n", color)
+		return
+	}
+	colorTheme, err := diff.ParseTheme(theme)
+	if err != nil {
+		fmt.Printf("Invalid theme: %v\n", err)
+		return
+	}
+	showStat := false
+	if stat {
+		// --stat on its own prints just the summary.
+		if format == "" && tmpl == "" {
+			format = string(diff.OutputStat)
+		} else {
+			showStat = true
+		}
+	}
+
 	opts := []interface{}{
-		diff.TermMode(term),
+		diff.TermMode(useColor(color, term)),
+		colorTheme,
 		diff.Interactive(interactive),
 		diff.MaxLines(maxLines),
+		diff.Markers(markers),
+		diff.OutputFormat(format),
+		diff.MaxDiffLines(maxDiffLines),
+		diff.TabWidth(tabWidth),
+		diff.Width(outputWidth(width)),
+		diff.Overflow(overflow),
+		diff.Layout(layout),
+		diff.LineNumbers(lineNumbers),
+		diff.Stat(showStat),
+	}
+	if context >= 0 {
+		opts = append(opts, diff.ContextLines(context), diff.Folding(true))
+	}
+	if tmpl != "" {
+		r, err := templateRenderer(tmpl)
+		if err != nil {
+			fmt.Printf("Error loading template: %v\n", err)
+			return
+		}
+		opts = append(opts, r)
 	}
 
 	if selectFile != "" {
@@ -87,28 +231,137 @@ func DiffFiles(path1, path2 string, term bool, interactive bool, maxLines int, s
 		opts = append(opts, filter)
 	}
 
-	output, err := diff.Diff(path1, path2, opts...)
+	if htmlDir != "" {
+		if err := diff.WriteHTMLSite(htmlDir, path1, path2, opts...); err != nil {
+			fmt.Printf("Error writing HTML report: %v\n", err)
+			return
+		}
+		fmt.Printf("Wrote %s\n", filepath.Join(htmlDir, "index.html"))
+		return
+	}
+
+	err = printOutput(interactive, func(w io.Writer) error {
+		return diff.DiffTo(w, path1, path2, opts...)
+	})
 	if err != nil {
 		fmt.Printf("Error running diff: %v\n", err)
-		return
 	}
+}
+
+// printOutput runs render with standard output, or in interactive mode with
+// the input of less, so that output is shown as it is produced.
+func printOutput(interactive bool, render func(w io.Writer) error) error {
+	if !interactive {
+		return render(os.Stdout)
+	}
+	// Use less for paging
+	cmd := exec.Command("less", "-R")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		// Fallback if less is not found
+		return render(os.Stdout)
+	}
+	pager := &pagerWriter{w: stdin}
+	err = render(pager)
+	stdin.Close()
+	if waitErr := cmd.Wait(); err == nil {
+		err = waitErr
+	}
+	if pager.err != nil {
+		// The pager was closed before the end of the output.
+		return nil
+	}
+	return err
+}
+
+// pagerWriter records whether writing to the pager failed.
+type pagerWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *pagerWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	if err != nil {
+		p.err = err
+	}
+	return n, err
+}
+
+func validMarkers(markers string) bool {
+	switch diff.Markers(markers) {
+	case diff.MarkersNone, diff.MarkersCaret, diff.MarkersInline:
+		return true
+	}
+	return false
+}
+
+func validOverflow(overflow string) bool {
+	switch diff.Overflow(overflow) {
+	case "", diff.OverflowWrap, diff.OverflowTruncate:
+		return true
+	}
+	return false
+}
+
+func validLayout(layout string) bool {
+	switch diff.Layout(layout) {
+	case "", diff.LayoutAuto, diff.LayoutColumns, diff.LayoutStacked:
+		return true
+	}
+	return false
+}
+
+func validColor(color string) bool {
+	switch color {
+	case "", "auto", "always", "never":
+		return true
+	}
+	return false
+}
+
+// validFormat reports whether format names a registered renderer, so that
+// formats added with diff.RegisterRenderer can be selected too.
+func validFormat(format string) bool {
+	_, err := diff.NewRenderer(diff.OutputFormat(format))
+	return err == nil
+}
 
-	if interactive {
-		// Use less for paging
-		cmd := exec.Command("less", "-R")
-		cmd.Stdin = strings.NewReader(output)
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		if err := cmd.Run(); err != nil {
-			// Fallback if less fails or not found
-			fmt.Print(output)
+// templateRenderer renders with the built-in template of that name, or with
+// the template in the file if name is a path.
+func templateRenderer(name string) (diff.Renderer, error) {
+	var t *template.Template
+	var err error
+	if strings.HasSuffix(name, ".tmpl") || strings.ContainsRune(name, '/') || strings.ContainsRune(name, filepath.Separator) {
+		var text []byte
+		text, err = os.ReadFile(name)
+		if err != nil {
+			return nil, err
 		}
+		t, err = diff.ParseTemplate(filepath.Base(name), string(text))
 	} else {
-		fmt.Print(output)
+		t, err = diff.BuiltinTemplate(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return diff.NewTemplateRenderer(t), nil
+}
+
+func formatNames() string {
+	var names []string
+	for _, name := range diff.RendererNames() {
+		names = append(names, string(name))
 	}
+	return strings.Join(names, ", ")
 }
 
-// PatchFiles is a subcommand 'diff patch'
+// PatchFiles is a subcommand `diff patch`
 // Applies a patch file to a target directory.
 //
 // Flags:
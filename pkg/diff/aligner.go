@@ -3,7 +3,6 @@ package diff
 import (
 	"crypto/sha1"
 	"fmt"
-	"unicode"
 )
 
 func CalculateHash(s string) string {
@@ -14,7 +13,7 @@ func CalculateHash(s string) string {
 
 func AlignLines(a, b []string, opts *Options) []DiffLine {
 	if opts.LineUpFunc != nil {
-		return opts.LineUpFunc(a, b, opts)
+		return inferLineNumbers(opts.LineUpFunc(a, b, opts))
 	}
 
 	var result []DiffLine
@@ -43,14 +42,12 @@ func AlignLines(a, b []string, opts *Options) []DiffLine {
 
 	for ai < len(a) || bi < len(b) {
 		if ai >= len(a) {
-			t, ops := ComputeDiffType("", b[bi])
-			result = append(result, DiffLine{Left: "", Right: b[bi], Type: t, Ops: ops})
+			result = append(result, newDiffLine("", b[bi], 0, bi+1, opts))
 			bi++
 			continue
 		}
 		if bi >= len(b) {
-			t, ops := ComputeDiffType(a[ai], "")
-			result = append(result, DiffLine{Left: a[ai], Right: "", Type: t, Ops: ops})
+			result = append(result, newDiffLine(a[ai], "", ai+1, 0, opts))
 			ai++
 			continue
 		}
@@ -61,8 +58,7 @@ func AlignLines(a, b []string, opts *Options) []DiffLine {
 		if ha == hb {
 			// Hash match -> Align
 			// Even if content differs (collision), we treat as aligned modified line.
-			t, ops := ComputeDiffType(a[ai], b[bi])
-			result = append(result, DiffLine{Left: a[ai], Right: b[bi], Type: t, Ops: ops})
+			result = append(result, newDiffLine(a[ai], b[bi], ai+1, bi+1, opts))
 			ai++
 			bi++
 			continue
@@ -101,21 +97,18 @@ func AlignLines(a, b []string, opts *Options) []DiffLine {
 		if bestBj != -1 && (bestAj == -1 || bestBj-bi < bestAj-ai) {
 			// Insertion in b (skip b until bestBj)
 			for bi < bestBj {
-				t, ops := ComputeDiffType("", b[bi])
-				result = append(result, DiffLine{Left: "", Right: b[bi], Type: t, Ops: ops})
+				result = append(result, newDiffLine("", b[bi], 0, bi+1, opts))
 				bi++
 			}
 		} else if bestAj != -1 {
 			// Deletion in a (skip a until bestAj)
 			for ai < bestAj {
-				t, ops := ComputeDiffType(a[ai], "")
-				result = append(result, DiffLine{Left: a[ai], Right: "", Type: t, Ops: ops})
+				result = append(result, newDiffLine(a[ai], "", ai+1, 0, opts))
 				ai++
 			}
 		} else {
 			// Modification (no match found nearby)
-			t, ops := ComputeDiffType(a[ai], b[bi])
-			result = append(result, DiffLine{Left: a[ai], Right: b[bi], Type: t, Ops: ops})
+			result = append(result, newDiffLine(a[ai], b[bi], ai+1, bi+1, opts))
 			ai++
 			bi++
 		}
@@ -123,6 +116,30 @@ func AlignLines(a, b []string, opts *Options) []DiffLine {
 	return result
 }
 
+// inferLineNumbers fills in LeftNum and RightNum for results of a LineUpFunc
+// that does not track them. An empty cell opposite a non-empty one is assumed
+// to be a missing line; anything else is assumed to be present.
+func inferLineNumbers(diffs []DiffLine) []DiffLine {
+	for _, d := range diffs {
+		if d.LeftNum != 0 || d.RightNum != 0 {
+			return diffs
+		}
+	}
+	ln, rn := 0, 0
+	for i := range diffs {
+		d := &diffs[i]
+		if d.Left != "" || d.Right == "" {
+			ln++
+			d.LeftNum = ln
+		}
+		if d.Right != "" || d.Left == "" {
+			rn++
+			d.RightNum = rn
+		}
+	}
+	return diffs
+}
+
 func ComputeDiffType(a, b string) (DiffType, []Operation) {
 	if a == b {
 		return DiffEqual, nil
@@ -133,65 +150,7 @@ func ComputeDiffType(a, b string) (DiffType, []Operation) {
 	}
 
 	ops := getEditScript(a, b)
-
-	blocks := 0
-	inDiff := false
-	hasCharDiff := false
-	hasSpaceDiff := false
-	isOnlyEOL := true
-	hasEOLDiff := false
-
-	for _, op := range ops {
-		switch op.Type {
-		case OpMatch:
-			inDiff = false
-		case OpInsert, OpDelete:
-			if !inDiff {
-				blocks++
-				inDiff = true
-			}
-			for _, r := range op.Content {
-				if unicode.IsSpace(r) {
-					hasSpaceDiff = true
-					if r == '\r' || r == '\n' {
-						hasEOLDiff = true
-					} else {
-						isOnlyEOL = false
-					}
-				} else {
-					hasCharDiff = true
-					isOnlyEOL = false
-				}
-			}
-		}
-	}
-
-	if blocks == 0 {
-		return DiffEqual, ops
-	}
-
-	if hasEOLDiff && isOnlyEOL && !hasCharDiff {
-		return DiffEOL, ops
-	}
-
-	if !hasCharDiff && hasSpaceDiff {
-		return DiffSpace, ops
-	}
-	if hasCharDiff && hasSpaceDiff {
-		return DiffMixed, ops
-	}
-
-	if blocks == 1 {
-		return Diff1, ops
-	}
-	if blocks == 2 {
-		return Diff2, ops
-	}
-	if blocks < 10 {
-		return DiffType(fmt.Sprintf("%dd", blocks)), ops
-	}
-
-	return "+d", ops
+	return classifyOps(ops), ops
 }
 
 func getEditScript(s1, s2 string) []Operation {
@@ -207,11 +166,7 @@ func getEditScript(s1, s2 string) []Operation {
 			if r1[i-1] == r2[j-1] {
 				dp[i][j] = dp[i-1][j-1] + 1
 			} else {
-				if dp[i-1][j] >= dp[i][j-1] {
-					dp[i][j] = dp[i][j-1]
-				} else {
-					dp[i][j] = dp[i][j-1]
-				}
+				dp[i][j] = max(dp[i-1][j], dp[i][j-1])
 			}
 		}
 	}
@@ -0,0 +1,119 @@
+package diff
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// Classifier decides what kind of difference an aligned pair of lines is.
+// It receives both lines along with the character-level edit script between
+// them (nil when the lines are identical).
+type Classifier interface {
+	Classify(left, right string, ops []Operation) Classification
+}
+
+// Classification is the result of classifying a pair of lines. Type may be one
+// of the built-in DiffType constants or a custom symbol; Meta carries any
+// extra information the classifier wants to attach to the DiffLine.
+type Classification struct {
+	Type DiffType
+	Meta map[string]string
+}
+
+// ClassifierFunc adapts an ordinary function to the Classifier interface.
+type ClassifierFunc func(left, right string, ops []Operation) Classification
+
+func (f ClassifierFunc) Classify(left, right string, ops []Operation) Classification {
+	return f(left, right, ops)
+}
+
+// DefaultClassifier implements the built-in classification used by
+// ComputeDiffType.
+type DefaultClassifier struct{}
+
+func (DefaultClassifier) Classify(left, right string, ops []Operation) Classification {
+	if left == right {
+		return Classification{Type: DiffEqual}
+	}
+	return Classification{Type: classifyOps(ops)}
+}
+
+// newDiffLine builds the DiffLine for an aligned pair, running the configured
+// Classifier (or DefaultClassifier) over it. A line number of 0 marks that side
+// as missing.
+func newDiffLine(left, right string, leftNum, rightNum int, opts *Options) DiffLine {
+	var ops []Operation
+	if left != right {
+		ops = getEditScript(left, right)
+	}
+	classifier := opts.Classifier
+	if classifier == nil {
+		classifier = DefaultClassifier{}
+	}
+	if opts.Tolerance != nil {
+		classifier = toleranceClassifier{tolerance: *opts.Tolerance, next: classifier}
+	}
+	c := classifier.Classify(left, right, ops)
+	return DiffLine{Left: left, Right: right, Type: c.Type, Ops: ops, Meta: c.Meta, LeftNum: leftNum, RightNum: rightNum}
+}
+
+func classifyOps(ops []Operation) DiffType {
+	blocks := 0
+	inDiff := false
+	hasCharDiff := false
+	hasSpaceDiff := false
+	isOnlyEOL := true
+	hasEOLDiff := false
+
+	for _, op := range ops {
+		switch op.Type {
+		case OpMatch:
+			inDiff = false
+		case OpInsert, OpDelete:
+			if !inDiff {
+				blocks++
+				inDiff = true
+			}
+			for _, r := range op.Content {
+				if unicode.IsSpace(r) {
+					hasSpaceDiff = true
+					if r == '\r' || r == '\n' {
+						hasEOLDiff = true
+					} else {
+						isOnlyEOL = false
+					}
+				} else {
+					hasCharDiff = true
+					isOnlyEOL = false
+				}
+			}
+		}
+	}
+
+	if blocks == 0 {
+		return DiffEqual
+	}
+
+	if hasEOLDiff && isOnlyEOL && !hasCharDiff {
+		return DiffEOL
+	}
+
+	if !hasCharDiff && hasSpaceDiff {
+		return DiffSpace
+	}
+	if hasCharDiff && hasSpaceDiff {
+		return DiffMixed
+	}
+
+	if blocks == 1 {
+		return Diff1
+	}
+	if blocks == 2 {
+		return Diff2
+	}
+	if blocks < 10 {
+		return DiffType(fmt.Sprintf("%dd", blocks))
+	}
+
+	return "+d"
+}
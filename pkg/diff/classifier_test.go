@@ -0,0 +1,72 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+// commentClassifier treats edits confined to a trailing "//" comment as
+// insignificant and reports them with a custom symbol.
+var commentClassifier = ClassifierFunc(func(left, right string, ops []Operation) Classification {
+	code := func(s string) string {
+		if i := strings.Index(s, "//"); i >= 0 {
+			return strings.TrimSpace(s[:i])
+		}
+		return strings.TrimSpace(s)
+	}
+	if left != right && code(left) == code(right) {
+		return Classification{Type: "cmt", Meta: map[string]string{"reason": "comment only"}}
+	}
+	return DefaultClassifier{}.Classify(left, right, ops)
+})
+
+func TestDefaultClassifierMatchesComputeDiffType(t *testing.T) {
+	pairs := [][2]string{
+		{"abc", "abc"},
+		{"abc", "axc"},
+		{"abcde", "azcxe"},
+		{"a b", "a  b"},
+		{"", "a"},
+		{"1.2.3.4", "1x2x3x4"},
+	}
+	for _, p := range pairs {
+		want, ops := ComputeDiffType(p[0], p[1])
+		got := DefaultClassifier{}.Classify(p[0], p[1], ops)
+		if got.Type != want {
+			t.Errorf("Classify(%q, %q) = %v, want %v", p[0], p[1], got.Type, want)
+		}
+	}
+}
+
+func TestCustomClassifier(t *testing.T) {
+	opts := NewOptions(Classifier(commentClassifier))
+	a := []string{"x := 1 // one", "y := 2"}
+	b := []string{"x := 1 // uno", "y := 3"}
+
+	diffs := AlignLines(a, b, opts)
+	if len(diffs) != 2 {
+		t.Fatalf("Expected 2 diff lines, got %d", len(diffs))
+	}
+	if diffs[0].Type != "cmt" {
+		t.Errorf("Line 0 should be classified as cmt, got %v", diffs[0].Type)
+	}
+	if diffs[0].Meta["reason"] != "comment only" {
+		t.Errorf("Line 0 metadata not propagated: %v", diffs[0].Meta)
+	}
+	if len(diffs[0].Ops) == 0 {
+		t.Error("Line 0 should still carry the edit script")
+	}
+	if diffs[1].Type != Diff1 {
+		t.Errorf("Line 1 should fall back to Diff1, got %v", diffs[1].Type)
+	}
+}
+
+func TestFormatDiffCustomSymbolWidth(t *testing.T) {
+	opts := NewOptions(Classifier(commentClassifier))
+	diffs := AlignLines([]string{"a // x", "b"}, []string{"a // y", "b"}, opts)
+	got := FormatDiff(diffs, opts)
+	expected := "a // x cmt a // y\nb      ==  b\n"
+	if got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+}
@@ -0,0 +1,109 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatContext renders lines as a classic context diff (diff -c). Files are
+// named by opts.Labels and opts.Timestamps, and each hunk has
+// opts.ContextLines lines of context. Identical inputs produce no output.
+func FormatContext(lines []DiffLine, opts *Options) string {
+	es := newEditScript(lines)
+	hunks := es.hunks(opts.ContextLines)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(styleLine("*** "+fileHeader(opts.Labels.Left, opts.Timestamps.Left), opts.theme().File, opts))
+	sb.WriteString(styleLine("--- "+fileHeader(opts.Labels.Right, opts.Timestamps.Right), opts.theme().File, opts))
+	for _, h := range hunks {
+		marks := contextMarks(h.edits)
+		hasDelete, hasInsert := false, false
+		for _, e := range h.edits {
+			hasDelete = hasDelete || e.kind == editDelete
+			hasInsert = hasInsert || e.kind == editInsert
+		}
+
+		sb.WriteString("***************\n")
+		sb.WriteString(styleLine(fmt.Sprintf("*** %s ****", contextRange(h.leftStart, h.leftCount)), opts.theme().Header, opts))
+		if hasDelete {
+			for i, e := range h.edits {
+				if e.kind != editInsert {
+					writeContextLine(&sb, es, e, marks[i], opts)
+				}
+			}
+		}
+		sb.WriteString(styleLine(fmt.Sprintf("--- %s ----", contextRange(h.rightStart, h.rightCount)), opts.theme().Header, opts))
+		if hasInsert {
+			for i, e := range h.edits {
+				if e.kind != editDelete {
+					writeContextLine(&sb, es, e, marks[i], opts)
+				}
+			}
+		}
+	}
+	return sb.String()
+}
+
+// contextMarks returns the context diff marker for each edit: "!" for lines in
+// a group containing both deletions and insertions, "-" or "+" for lines in a
+// group that only deletes or only inserts, and " " for unchanged lines.
+func contextMarks(edits []edit) []string {
+	marks := make([]string, len(edits))
+	for i := 0; i < len(edits); {
+		if edits[i].kind == editEqual {
+			marks[i] = " "
+			i++
+			continue
+		}
+		end := i
+		hasDelete, hasInsert := false, false
+		for end < len(edits) && edits[end].kind != editEqual {
+			hasDelete = hasDelete || edits[end].kind == editDelete
+			hasInsert = hasInsert || edits[end].kind == editInsert
+			end++
+		}
+		for ; i < end; i++ {
+			switch {
+			case hasDelete && hasInsert:
+				marks[i] = "!"
+			case hasDelete:
+				marks[i] = "-"
+			default:
+				marks[i] = "+"
+			}
+		}
+	}
+	return marks
+}
+
+func writeContextLine(sb *strings.Builder, es *editScript, e edit, mark string, opts *Options) {
+	text := mark + " " + e.text
+	switch mark {
+	case " ":
+		sb.WriteString(text + "\n")
+	case "+":
+		sb.WriteString(styleLine(text, opts.theme().Inserted, opts))
+	case "-":
+		sb.WriteString(styleLine(text, opts.theme().Deleted, opts))
+	default:
+		sb.WriteString(styleLine(text, opts.theme().Modified, opts))
+	}
+	if es.missingEOL(e) {
+		sb.WriteString(noEOLMarker + "\n")
+	}
+}
+
+// contextRange formats a hunk range as "first,last", or just the line number
+// for ranges of one line. Empty ranges are given by the line before them.
+func contextRange(before, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d", before)
+	case 1:
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, before+count)
+}
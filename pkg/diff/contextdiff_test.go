@@ -0,0 +1,94 @@
+package diff
+
+import "testing"
+
+func TestFormatContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		context  int
+		expected string
+	}{
+		{
+			name:     "identical",
+			a:        "a\n",
+			b:        "a\n",
+			context:  3,
+			expected: "",
+		},
+		{
+			name:    "delete and insert",
+			a:       "a\nb\nc\n",
+			b:       "a\nc\nd\n",
+			context: 3,
+			expected: `*** a
+--- b
+***************
+*** 1,3 ****
+  a
+- b
+  c
+--- 1,3 ----
+  a
+  c
++ d
+`,
+		},
+		{
+			name:    "changed line without newline",
+			a:       "x\ny",
+			b:       "x\ny\n",
+			context: 3,
+			expected: `*** a
+--- b
+***************
+*** 1,2 ****
+  x
+! y
+\ No newline at end of file
+--- 1,2 ----
+  x
+! y
+`,
+		},
+		{
+			name:    "new file",
+			a:       "",
+			b:       "a\nc\n",
+			context: 3,
+			expected: `*** a
+--- b
+***************
+*** 0 ****
+--- 1,2 ----
++ a
++ c
+`,
+		},
+		{
+			name:    "zero context",
+			a:       "a\nb\nc\n",
+			b:       "a\nc\nd\n",
+			context: 0,
+			expected: `*** a
+--- b
+***************
+*** 2 ****
+- b
+--- 1 ----
+***************
+*** 3 ****
+--- 3 ----
++ d
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(tt.a, tt.b, OutputContext, ContextLines(tt.context))
+			if got != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
@@ -1,21 +1,34 @@
 package diff
 
 import (
+	"io"
 	"strings"
 )
 
+// Compare aligns a and b, which are strings or string slices, and returns
+// them rendered in the configured format. If TestingT is set and the inputs
+// differ, the output is reported to it as an error.
+//
+// A format that fails to render, such as a template that fails to execute,
+// returns the output written before the failure. The error is reported to
+// TestingT if set; use CompareTo to get it otherwise.
 func Compare(a, b interface{}, options ...interface{}) string {
 	opts := NewOptions(options...)
 
 	aLines := toStringSlice(a)
 	bLines := toStringSlice(b)
 
-	diffs := AlignLines(aLines, bLines, opts)
-	output := FormatDiff(diffs, opts)
+	diffs := align(aLines, bLines, opts)
+	var sb strings.Builder
+	err := renderLines(&sb, diffs, opts)
+	output := sb.String()
 	if opts.TestingT != nil {
 		opts.TestingT.Helper()
+		if err != nil {
+			opts.TestingT.Errorf("rendering output: %v", err)
+		}
 		for _, diff := range diffs {
-			if diff.Type != DiffEqual {
+			if !diff.Type.IsEqual() {
 				opts.TestingT.Errorf("%s", output)
 				break
 			}
@@ -24,6 +37,44 @@ func Compare(a, b interface{}, options ...interface{}) string {
 	return output
 }
 
+// CompareTo is Compare writing its output to w as it is rendered rather than
+// returning it, and returning any error from w or the renderer instead. It
+// does not report to a TestingT.
+func CompareTo(w io.Writer, a, b interface{}, options ...interface{}) error {
+	opts := NewOptions(options...)
+	return renderLines(w, align(toStringSlice(a), toStringSlice(b), opts), opts)
+}
+
+// align runs the configured Scrubbers over both sides before aligning them,
+// restoring the original text afterwards if ShowOriginal is set.
+func align(a, b []string, opts *Options) []DiffLine {
+	if len(opts.Scrubbers) == 0 {
+		return AlignLines(a, b, opts)
+	}
+	scrubbedA := opts.Scrubbers.scrubLines(a)
+	scrubbedB := opts.Scrubbers.scrubLines(b)
+	diffs := AlignLines(scrubbedA, scrubbedB, opts)
+	if opts.ShowOriginal {
+		restoreOriginals(diffs, a, scrubbedA, b, scrubbedB)
+	}
+	return diffs
+}
+
+// renderLines writes lines to w in the configured OutputFormat.
+func renderLines(w io.Writer, lines []DiffLine, opts *Options) error {
+	r, err := renderer(opts)
+	if err != nil {
+		return err
+	}
+	if err := r.BeginRun(w, opts); err != nil {
+		return err
+	}
+	if err := renderFile(w, r, newFileDiff(lines, opts), opts); err != nil {
+		return err
+	}
+	return r.EndRun(w, opts)
+}
+
 func toStringSlice(v interface{}) []string {
 	switch t := v.(type) {
 	case string:
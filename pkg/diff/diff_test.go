@@ -1,6 +1,8 @@
 package diff
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -57,6 +59,15 @@ func TestComputeDiffType(t *testing.T) {
 	}
 }
 
+func TestComputeDiffTypeOps(t *testing.T) {
+	_, ops := ComputeDiffType("the cat sat", "the dog sat")
+	got := fmt.Sprint(CoalesceOps(ops))
+	expected := "[{match the } {insert dog} {delete cat} {match  sat}]"
+	if got != expected {
+		t.Errorf("Expected ops %s, got %s", expected, got)
+	}
+}
+
 func TestCompareOutput(t *testing.T) {
 	a := "line1\nline2\nline3"
 	b := "line1\nline2 modified\nline3"
@@ -75,3 +86,24 @@ func TestCompareOutput(t *testing.T) {
 		t.Error("Output missing buffer symbol q for modified line. Output:\n" + output)
 	}
 }
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestCompareTo(t *testing.T) {
+	var sb strings.Builder
+	if err := CompareTo(&sb, "a\nb", "a\nc", OutputUnified); err != nil {
+		t.Fatalf("CompareTo failed: %v", err)
+	}
+	if expected := Compare("a\nb", "a\nc", OutputUnified); sb.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, sb.String())
+	}
+
+	if err := CompareTo(failingWriter{}, "a", "b"); err == nil || err.Error() != "disk full" {
+		t.Errorf("Expected the write error, got %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package diff
+
+// editKind classifies a line in an edit script.
+type editKind int
+
+const (
+	editEqual editKind = iota
+	editDelete
+	editInsert
+)
+
+// edit is a single line of a line-oriented edit script, the model shared by
+// the patch-style renderers (unified, context, ...). An edit is equal if its
+// line counts as equal (see DiffType.IsEqual) and both sides end in the same
+// way; lines that are only equal within Tolerance keep their right-hand text.
+type edit struct {
+	kind  editKind
+	text  string
+	left  int // 1-based line number on the left, 0 for insertions
+	right int // 1-based line number on the right, 0 for deletions
+}
+
+// editScript is the edit script for a pair of inputs.
+type editScript struct {
+	edits []edit
+	// nLeft and nRight count the lines on each side, not including the empty
+	// element that follows a trailing newline.
+	nLeft, nRight int
+	// leftNoEOL and rightNoEOL are set when the last line of that side is not
+	// terminated by a newline.
+	leftNoEOL, rightNoEOL bool
+}
+
+// newEditScript converts aligned lines into an edit script. Inputs are
+// treated as text split on "\n": a final empty line stands for the newline
+// ending the previous one and is not itself a line.
+func newEditScript(lines []DiffLine) *editScript {
+	es := &editScript{}
+	var lastLeft, lastRight string
+	for _, line := range lines {
+		if line.LeftNum > es.nLeft {
+			es.nLeft, lastLeft = line.LeftNum, line.Left
+		}
+		if line.RightNum > es.nRight {
+			es.nRight, lastRight = line.RightNum, line.Right
+		}
+	}
+	if es.nLeft > 0 {
+		if lastLeft == "" {
+			es.nLeft--
+		} else {
+			es.leftNoEOL = true
+		}
+	}
+	if es.nRight > 0 {
+		if lastRight == "" {
+			es.nRight--
+		} else {
+			es.rightNoEOL = true
+		}
+	}
+
+	var pending []edit // deletions and insertions not yet flushed
+	flush := func() {
+		for _, e := range pending {
+			if e.kind == editDelete {
+				es.edits = append(es.edits, e)
+			}
+		}
+		for _, e := range pending {
+			if e.kind == editInsert {
+				es.edits = append(es.edits, e)
+			}
+		}
+		pending = pending[:0]
+	}
+	for _, line := range lines {
+		hasLeft := line.LeftNum > 0 && line.LeftNum <= es.nLeft
+		hasRight := line.RightNum > 0 && line.RightNum <= es.nRight
+		if hasLeft && hasRight && line.Type.IsEqual() && es.leftMissingEOL(line.LeftNum) == es.rightMissingEOL(line.RightNum) {
+			flush()
+			es.edits = append(es.edits, edit{kind: editEqual, text: line.Right, left: line.LeftNum, right: line.RightNum})
+			continue
+		}
+		if hasLeft {
+			pending = append(pending, edit{kind: editDelete, text: line.Left, left: line.LeftNum})
+		}
+		if hasRight {
+			pending = append(pending, edit{kind: editInsert, text: line.Right, right: line.RightNum})
+		}
+	}
+	flush()
+	return es
+}
+
+// leftMissingEOL reports whether left line n is the last one and has no
+// trailing newline.
+func (es *editScript) leftMissingEOL(n int) bool {
+	return es.leftNoEOL && n == es.nLeft
+}
+
+// rightMissingEOL reports whether right line n is the last one and has no
+// trailing newline.
+func (es *editScript) rightMissingEOL(n int) bool {
+	return es.rightNoEOL && n == es.nRight
+}
+
+// missingEOL reports whether e is the last line of its side and lacks a
+// trailing newline.
+func (es *editScript) missingEOL(e edit) bool {
+	if e.kind == editInsert {
+		return es.rightMissingEOL(e.right)
+	}
+	return es.leftMissingEOL(e.left)
+}
+
+// isTerminator reports whether line is just the empty element after a
+// trailing newline on both sides, which is not a line of either input.
+func (es *editScript) isTerminator(line DiffLine) bool {
+	return line.Type == DiffEqual && line.LeftNum > es.nLeft && line.RightNum > es.nRight
+}
+
+// editHunk is a range of edits containing changes and their surrounding
+// context.
+type editHunk struct {
+	edits []edit
+	// leftStart and rightStart are the number of lines on each side that
+	// come before the hunk.
+	leftStart, rightStart int
+	leftCount, rightCount int
+}
+
+// hunks groups the changes in the script into hunks with up to context lines
+// of unchanged text around them, in the same way as Hunks.
+func (es *editScript) hunks(context int) []editHunk {
+	var result []editHunk
+	for _, r := range hunkRanges(len(es.edits), context, func(i int) bool { return es.edits[i].kind != editEqual }) {
+		result = append(result, es.newHunk(r[0], r[1]))
+	}
+	return result
+}
+
+func (es *editScript) newHunk(start, end int) editHunk {
+	h := editHunk{edits: es.edits[start:end]}
+	for _, e := range es.edits[:start] {
+		if e.kind != editInsert {
+			h.leftStart++
+		}
+		if e.kind != editDelete {
+			h.rightStart++
+		}
+	}
+	for _, e := range h.edits {
+		if e.kind != editInsert {
+			h.leftCount++
+		}
+		if e.kind != editDelete {
+			h.rightCount++
+		}
+	}
+	return h
+}
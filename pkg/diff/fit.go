@@ -0,0 +1,71 @@
+package diff
+
+import "strings"
+
+// Width limits side-by-side output to this many columns, shared between the
+// two sides. Lines that do not fit are wrapped or truncated according to
+// Overflow. 0 means no limit.
+type Width int
+
+// Overflow selects what happens to lines wider than their column when a Width
+// is set.
+type Overflow string
+
+const (
+	OverflowWrap     Overflow = "wrap"     // Continue on following rows (the default)
+	OverflowTruncate Overflow = "truncate" // Cut short and end with an ellipsis
+)
+
+const ellipsis = "…"
+
+// fitSide splits side into rows of at most col columns, or truncates it to
+// one row, as configured. Sides that fit are returned as they are.
+func fitSide(side renderedSide, col int, opts *Options) []renderedSide {
+	if opts.Width <= 0 || side.width <= col {
+		return []renderedSide{side}
+	}
+	if opts.Overflow == OverflowTruncate {
+		if col <= 1 {
+			return []renderedSide{newRenderedSide([]piece{{text: ellipsis, width: 1}}, opts)}
+		}
+		rows := splitPieces(side.pieces, col-1)
+		return []renderedSide{newRenderedSide(append(rows[0], piece{text: ellipsis, width: 1}), opts)}
+	}
+	var sides []renderedSide
+	for _, row := range splitPieces(side.pieces, col) {
+		sides = append(sides, newRenderedSide(row, opts))
+	}
+	return sides
+}
+
+// splitPieces breaks pieces into rows no wider than col, splitting pieces
+// where needed so that each part keeps its operation. A character wider than
+// col is given a row of its own.
+func splitPieces(pieces []piece, col int) [][]piece {
+	var rows [][]piece
+	var row []piece
+	rowWidth := 0
+	for _, p := range pieces {
+		var part strings.Builder
+		partWidth := 0
+		for _, r := range p.text {
+			w := runeWidth(r)
+			if rowWidth+partWidth+w > col && rowWidth+partWidth > 0 {
+				if part.Len() > 0 {
+					row = append(row, piece{text: part.String(), width: partWidth, op: p.op})
+				}
+				rows = append(rows, row)
+				row, rowWidth = nil, 0
+				part.Reset()
+				partWidth = 0
+			}
+			part.WriteRune(r)
+			partWidth += w
+		}
+		if part.Len() > 0 {
+			row = append(row, piece{text: part.String(), width: partWidth, op: p.op})
+			rowWidth += partWidth
+		}
+	}
+	return append(rows, row)
+}
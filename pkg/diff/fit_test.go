@@ -0,0 +1,91 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatDiffWidth(t *testing.T) {
+	a := "abcdefghijklmnop\n"
+	b := "abcdefghijklmnoX\n"
+	tests := []struct {
+		name     string
+		options  []interface{}
+		expected []string
+	}{
+		{
+			name:    "wrap",
+			options: []interface{}{Width(21)},
+			expected: []string{
+				"abcdefgh 1d abcdefghi",
+				"ijklmnop    jklmnoX",
+				"         ==",
+			},
+		},
+		{
+			name:    "truncate",
+			options: []interface{}{Width(21), OverflowTruncate},
+			expected: []string{
+				"abcdefg… 1d abcdefgh…",
+				"         ==",
+			},
+		},
+		{
+			name:    "carets follow wrapped text",
+			options: []interface{}{Width(21), MarkersCaret},
+			expected: []string{
+				"abcdefgh 1d abcdefghi",
+				"ijklmnop    jklmnoX",
+				"       ^          ^",
+				"         ==",
+			},
+		},
+		{
+			name:    "colours follow wrapped text",
+			options: []interface{}{Width(21), TermMode(true)},
+			expected: []string{
+				"abcdefgh\033[31m 1d \033[0mabcdefghi",
+				"ijklmno\033[31mp\033[0m    jklmno\033[32mX\033[0m",
+				"        \033[32m ==\033[0m",
+			},
+		},
+		{
+			name:    "fits",
+			options: []interface{}{Width(80)},
+			expected: []string{
+				"abcdefghijklmnop 1d abcdefghijklmnoX",
+				"                 ==",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(a, b, tt.options...)
+			expected := strings.Join(tt.expected, "\n") + "\n"
+			if got != expected {
+				t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatDiffWidthGivesSpareColumns(t *testing.T) {
+	// The right side only needs 3 columns, so the left gets the rest.
+	got := Compare("a long line on the left\n", "new\n", Width(20))
+	expected := strings.Join([]string{
+		"a long line o q  new",
+		"n the left",
+		"              ==",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+}
+
+func TestSplitPiecesWide(t *testing.T) {
+	rows := splitPieces([]piece{{text: "日本語", width: 6}}, 5)
+	if len(rows) != 2 || rows[0][0].text != "日本" || rows[1][0].text != "語" {
+		t.Errorf("Unexpected rows: %+v", rows)
+	}
+}
@@ -5,94 +5,301 @@ import (
 	"strings"
 )
 
+// Markers selects a plain-text way of showing character-level changes, for
+// output where colours are unavailable.
+type Markers string
+
+const (
+	MarkersNone   Markers = ""
+	MarkersCaret  Markers = "caret"  // A row of ^ beneath each changed line
+	MarkersInline Markers = "inline" // [-deleted-] and {+inserted+} brackets
+)
+
 func FormatDiff(lines []DiffLine, opts *Options) string {
-	maxLeft := 0
-	for _, line := range lines {
-		if len(line.Left) > maxLeft {
-			maxLeft = len(line.Left)
+	rows := sideBySideRows(lines, opts)
+	rendered := make([][2]renderedSide, len(rows))
+	maxLeft, maxRight := 0, 0
+	// Built-in symbols fit in 2 chars; custom classifiers may use longer ones.
+	symbolWidth := 2
+	for i, r := range rows {
+		if r.folded > 0 {
+			continue
+		}
+		line := lines[r.line]
+		left, right := renderDiffLine(line, opts)
+		rendered[i] = [2]renderedSide{left, right}
+		if left.width > maxLeft {
+			maxLeft = left.width
 		}
+		if right.width > maxRight {
+			maxRight = right.width
+		}
+		if len(line.Type) > symbolWidth {
+			symbolWidth = len(line.Type)
+		}
+	}
+	gutters := newGutters(lines, opts)
+	if stackedLayout(maxLeft, maxRight, symbolWidth+gutters.width(), opts) {
+		return formatStacked(lines, opts)
 	}
+	leftCol, rightCol := columnWidths(maxLeft, maxRight, symbolWidth+gutters.width(), opts)
 
 	var sb strings.Builder
-	for _, line := range lines {
-		leftStr, rightStr := renderDiffLine(line, opts)
-
-		// Padding logic: must pad based on VISIBLE length (line.Left), not ANSI length.
-		visibleLeftLen := len(line.Left)
-		padding := ""
-		if maxLeft > visibleLeftLen {
-			padding = strings.Repeat(" ", maxLeft-visibleLeftLen)
+	for i, r := range rows {
+		if r.folded > 0 {
+			sb.WriteString(foldMarker(r.folded, opts))
+			continue
 		}
+		line := lines[r.line]
+		lefts := fitSide(rendered[i][0], leftCol, opts)
+		rights := fitSide(rendered[i][1], rightCol, opts)
+		for row := 0; row < len(lefts) || row < len(rights); row++ {
+			var left, right renderedSide
+			if row < len(lefts) {
+				left = lefts[row]
+			}
+			if row < len(rights) {
+				right = rights[row]
+			}
 
-		symbol := string(line.Type)
-		// Buffer width: space + 2 chars + space = 4 chars
-		buffer := fmt.Sprintf(" %-2s ", symbol)
-		// If the right side is empty, we don't need the trailing space in the buffer.
-		// This makes the output look cleaner when there is no right-side content.
-		if rightStr == "" {
-			buffer = strings.TrimRight(buffer, " ")
-		}
+			// Padding logic: must pad based on VISIBLE width, not ANSI length.
+			padding := ""
+			if leftCol > left.width {
+				padding = strings.Repeat(" ", leftCol-left.width)
+			}
 
-		if opts.TermMode {
-			buffer = colorizeSymbol(buffer, line.Type)
-		}
+			// Continuation rows of wrapped lines leave the symbol column and
+			// line numbers blank.
+			symbol := ""
+			leftNum, rightNum := 0, 0
+			if row == 0 {
+				symbol = string(line.Type)
+				leftNum, rightNum = line.LeftNum, line.RightNum
+			}
+			leftGutter := gutters.left.format(leftNum, opts)
+			rightText := right.text
+			if rightText != "" || gutters.right.shows(rightNum) {
+				rightText = gutters.right.format(rightNum, opts) + rightText
+			}
+			// Buffer width: space + symbol column (2 chars by default) + space
+			buffer := fmt.Sprintf(" %-*s ", symbolWidth, symbol)
+			// If the right side is empty, we don't need the trailing space in the buffer.
+			// This makes the output look cleaner when there is no right-side content.
+			if rightText == "" {
+				buffer = strings.TrimRight(buffer, " ")
+				if buffer == "" {
+					padding = ""
+				}
+			}
 
-		sb.WriteString(leftStr)
-		sb.WriteString(padding)
-		sb.WriteString(buffer)
-		sb.WriteString(rightStr)
-		sb.WriteString("\n")
+			if opts.TermMode && symbol != "" {
+				buffer = colorizeSymbol(buffer, line.Type, opts.theme())
+			}
+
+			sb.WriteString(leftGutter)
+			sb.WriteString(left.text)
+			sb.WriteString(padding)
+			sb.WriteString(buffer)
+			sb.WriteString(rightText)
+			sb.WriteString("\n")
+
+			if left.carets != "" || right.carets != "" {
+				caretRow := strings.Repeat(" ", gutters.left.width()) + left.carets +
+					strings.Repeat(" ", leftCol-len(left.carets)+symbolWidth+2+gutters.right.width()) + right.carets
+				sb.WriteString(strings.TrimRight(caretRow, " "))
+				sb.WriteString("\n")
+			}
+		}
 	}
 	return sb.String()
 }
 
-func renderDiffLine(line DiffLine, opts *Options) (string, string) {
-	if !opts.TermMode {
-		return line.Left, line.Right
+// columnWidths returns the widths of the left and right columns. Without a
+// Width they are as wide as their widest line; otherwise the space left after
+// the reserved columns (the symbol column and gutters) is shared between
+// them, giving any space one side does not need to the other.
+func columnWidths(maxLeft, maxRight, reserved int, opts *Options) (int, int) {
+	if opts.Width <= 0 {
+		return maxLeft, maxRight
 	}
-
-	if line.Type == DiffEqual {
-		return line.Left, line.Right
+	available := opts.Width - reserved - 2
+	if available < 2 {
+		return 1, 1
 	}
-
-	// If no ops (e.g. empty lines or special case), fallback
-	if len(line.Ops) == 0 {
-		// Should generally have ops if ComputeDiffType was called.
-		// If line.Left and line.Right are non-empty but ops empty, it means match?
-		// But type != DiffEqual.
-		return line.Left, line.Right
+	left := available / 2
+	if maxRight < available-left {
+		left = available - maxRight
+	}
+	if left > maxLeft {
+		left = maxLeft
 	}
+	return left, available - left
+}
 
-	var leftSB, rightSB strings.Builder
+// span is a run of text on one side of a DiffLine together with the edit
+// operation that produced it.
+type span struct {
+	text string
+	op   OpType
+}
 
+// lineSpans splits a DiffLine into the spans making up its left and right
+// sides. Lines without character-level information are a single OpMatch span
+// per side.
+func lineSpans(line DiffLine) (left, right []span) {
+	if line.Type == DiffEqual || len(line.Ops) == 0 {
+		return []span{{text: line.Left}}, []span{{text: line.Right}}
+	}
 	for _, op := range line.Ops {
 		switch op.Type {
 		case OpMatch:
-			leftSB.WriteString(op.Content)
-			rightSB.WriteString(op.Content)
+			left = append(left, span{text: op.Content})
+			right = append(right, span{text: op.Content})
 		case OpDelete:
-			leftSB.WriteString(colorize(op.Content, "31")) // Red
+			left = append(left, span{text: op.Content, op: OpDelete})
 		case OpInsert:
-			rightSB.WriteString(colorize(op.Content, "32")) // Green
+			right = append(right, span{text: op.Content, op: OpInsert})
 		}
 	}
+	return left, right
+}
+
+// renderedSide is one side of a DiffLine ready for output.
+type renderedSide struct {
+	text   string  // possibly containing ANSI colour codes
+	width  int     // visible width of text
+	carets string  // marker row for MarkersCaret, empty if nothing changed
+	pieces []piece // text before colouring, for splitting to fit a Width
+}
 
-	return leftSB.String(), rightSB.String()
+// piece is a run of output text produced by a single edit operation.
+type piece struct {
+	text  string
+	width int
+	op    OpType
 }
 
+// renderDiffLine renders both sides of line. Tabs are expanded on the left
+// only: the right side is printed last, so it needs no padding, and is kept
+// as it is for Apply. With a Width both sides are expanded so that they can
+// be measured.
+func renderDiffLine(line DiffLine, opts *Options) (renderedSide, renderedSide) {
+	expandRight := opts.Width > 0
+	if !opts.TermMode && !opts.ShowInvisibles && opts.Markers == MarkersNone {
+		left := expandTabs(line.Left, 0, opts.TabWidth)
+		right := line.Right
+		if expandRight {
+			right = expandTabs(right, 0, opts.TabWidth)
+		}
+		return plainSide(left), plainSide(right)
+	}
+
+	left, right := lineSpans(line)
+	return renderSpans(left, true, opts), renderSpans(right, expandRight, opts)
+}
+
+func plainSide(text string) renderedSide {
+	w := displayWidth(text)
+	return renderedSide{text: text, width: w, pieces: []piece{{text: text, width: w}}}
+}
+
+// renderSpans writes out one side of a line, making invisible characters
+// visible, expanding tabs if expand is set, and marking or colouring changes
+// as configured.
+func renderSpans(spans []span, expand bool, opts *Options) renderedSide {
+	trailing := trailingSpaceStart(spans)
+	if opts.Markers == MarkersInline {
+		spans = mergeSpans(spans)
+	}
+	var pieces []piece
+	width := 0
+	pos := 0
+	for _, s := range spans {
+		text := s.text
+		if opts.ShowInvisibles {
+			text = showInvisibles(text, pos, trailing)
+		}
+		pos += len(s.text)
+		before, after := "", ""
+		if opts.Markers == MarkersInline {
+			switch s.op {
+			case OpDelete:
+				before, after = "[-", "-]"
+			case OpInsert:
+				before, after = "{+", "+}"
+			}
+		}
+		if expand {
+			text = expandTabs(text, width+len(before), opts.TabWidth)
+		}
+		text = before + text + after
+		w := displayWidth(text)
+		width += w
+		pieces = append(pieces, piece{text: text, width: w, op: s.op})
+	}
+	return newRenderedSide(pieces, opts)
+}
+
+// newRenderedSide colours pieces and builds their caret row as configured.
+func newRenderedSide(pieces []piece, opts *Options) renderedSide {
+	var sb, carets strings.Builder
+	hasCarets := false
+	width := 0
+	for _, p := range pieces {
+		width += p.width
+		if opts.Markers == MarkersCaret {
+			mark := " "
+			if p.op != OpMatch {
+				mark = "^"
+				hasCarets = true
+			}
+			carets.WriteString(strings.Repeat(mark, p.width))
+		}
+		text := p.text
+		if opts.TermMode {
+			switch p.op {
+			case OpDelete:
+				text = colorize(text, opts.theme().DeletedText)
+			case OpInsert:
+				text = colorize(text, opts.theme().InsertedText)
+			}
+		}
+		sb.WriteString(text)
+	}
+	side := renderedSide{text: sb.String(), width: width, pieces: pieces}
+	if hasCarets {
+		side.carets = carets.String()
+	}
+	return side
+}
+
+// mergeSpans joins adjacent spans produced by the same operation.
+func mergeSpans(spans []span) []span {
+	var merged []span
+	for _, s := range spans {
+		if n := len(merged); n > 0 && merged[n-1].op == s.op {
+			merged[n-1].text += s.text
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// colorize wraps s in the SGR colour code, if any.
 func colorize(s, code string) string {
+	if code == "" {
+		return s
+	}
 	return "\033[" + code + "m" + s + "\033[0m"
 }
 
-func colorizeSymbol(s string, t DiffType) string {
-	var code string
+func colorizeSymbol(s string, t DiffType, theme Theme) string {
 	switch t {
 	case DiffEqual:
-		code = "32" // Green
-	case DiffSpace, DiffEOL:
-		code = "33" // Yellow
-	default:
-		code = "31" // Red
+		return colorize(s, theme.Equal)
+	case DiffSpace, DiffEOL, DiffTolerance:
+		return colorize(s, theme.Minor)
 	}
-	return "\033[" + code + "m" + s + "\033[0m"
+	return colorize(s, theme.Changed)
 }
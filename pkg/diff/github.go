@@ -0,0 +1,71 @@
+package diff
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// FormatGitHub renders files as GitHub Actions workflow commands, one
+// ::error annotation for each change. Annotations point at the right file
+// and its line numbers, or at the left file of a deleted file, and carry the
+// unified hunk of the change as their message. Rows within Tolerance are not
+// reported.
+func FormatGitHub(files []*FileDiff, opts *Options) string {
+	var sb strings.Builder
+	for _, f := range files {
+		switch f.Status {
+		case StatusUnchanged:
+			continue
+		case StatusTypeMismatch:
+			writeGitHubCommand(&sb, annotationPath(f), 0, 0, "File type differs", strings.TrimSuffix(typeMismatch(f), "\n"))
+			continue
+		}
+		es := newEditScript(f.Lines)
+		for _, h := range es.hunks(0) {
+			line, count := h.rightStart, h.rightCount
+			if f.Status == StatusDeleted {
+				line, count = h.leftStart, h.leftCount
+			}
+			if count > 0 {
+				line++
+			}
+			end := max(line+count-1, line)
+			message := strings.Join(unifiedHunkLines(es, []editHunk{h}), "\n")
+			writeGitHubCommand(&sb, annotationPath(f), max(line, 1), max(end, 1), "File "+string(f.Status), message)
+		}
+	}
+	return sb.String()
+}
+
+// annotationPath is the file an annotation about f points at. Without a name
+// for that side, such as from Compare with empty Labels, it is the default
+// label of the side, "a" or "b".
+func annotationPath(f *FileDiff) string {
+	if f.Status == StatusDeleted {
+		return filepath.ToSlash(cmp.Or(f.Left, "a"))
+	}
+	return filepath.ToSlash(cmp.Or(f.Right, "b"))
+}
+
+// writeGitHubCommand writes an ::error workflow command. A line of 0 leaves
+// the line out.
+func writeGitHubCommand(sb *strings.Builder, file string, line, end int, title, message string) {
+	fmt.Fprintf(sb, "::error file=%s", escapeGitHubProperty(file))
+	if line > 0 {
+		fmt.Fprintf(sb, ",line=%d,endLine=%d", line, end)
+	}
+	fmt.Fprintf(sb, ",title=%s::%s\n", escapeGitHubProperty(title), escapeGitHubData(message))
+}
+
+// escapeGitHubData escapes the message of a workflow command, which must fit
+// on one line.
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a property value of a workflow command.
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
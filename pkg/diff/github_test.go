@@ -0,0 +1,57 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatGitHub(t *testing.T) {
+	t.Chdir(writeStatTree(t))
+	got, err := Diff("dir1", "dir2", OutputGitHub)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	lines := strings.Split(got, "\n")
+	expected := []string{
+		"::error file=dir2/mod.txt,line=2,endLine=2,title=File modified::@@ -2 +2 @@%0A-b%0A+B",
+		"::error file=dir2/mod.txt,line=4,endLine=4,title=File modified::@@ -3,0 +4 @@%0A+d",
+	}
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Errorf("Line %d: expected\n%s\ngot\n%s", i, e, lines[i])
+		}
+	}
+	if !strings.HasPrefix(lines[2], "::error file=dir2/sub/new.txt,line=1,endLine=40,title=File added::@@ -0,0 +1,40 @@%0A+line 1%0A") {
+		t.Errorf("Unexpected annotation for an added file:\n%s", lines[2])
+	}
+	if e := "::error file=dir1/sub/old.txt,line=1,endLine=1,title=File deleted::@@ -1 +0,0 @@%0A-gone"; lines[3] != e {
+		t.Errorf("Expected\n%s\ngot\n%s", e, lines[3])
+	}
+}
+
+func TestFormatGitHubEscaping(t *testing.T) {
+	got := Compare("a\n100%\nc\n", "a\nc\n", OutputGitHub, Labels{Left: "x", Right: "a,b:c.txt"})
+	expected := "::error file=a%2Cb%3Ac.txt,line=1,endLine=1,title=File modified::@@ -2 +1,0 @@%0A-100%25\n"
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestFormatGitHubTolerance(t *testing.T) {
+	if got := Compare("x 1.000\ny\n", "x 1.001\ny\n", OutputGitHub, Tolerance{Abs: 0.01}); got != "" {
+		t.Errorf("Expected no annotations within tolerance, got %q", got)
+	}
+	got := Compare("x 1.000\ny\n", "x 1.001\nz\n", OutputGitHub, Tolerance{Abs: 0.01})
+	expected := "::error file=b,line=2,endLine=2,title=File modified::@@ -2 +2 @@%0A-y%0A+z\n"
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestFormatGitHubNoLabels(t *testing.T) {
+	got := Compare("a\n", "b\n", OutputGitHub, Labels{})
+	expected := "::error file=b,line=1,endLine=1,title=File modified::@@ -1 +1 @@%0A-a%0A+b\n"
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,68 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LineNumbers adds gutters with the line number of each side to
+// side-by-side output. The gutter is blank on the side a line is missing
+// from.
+type LineNumbers bool
+
+// gutter is the line number column for one side.
+type gutter struct {
+	digits int // 0 when line numbers are off
+	last   int // Highest line number shown
+}
+
+type gutters struct {
+	left, right gutter
+}
+
+// newGutters sizes the gutters for lines. Like the patch formats, the empty
+// element after a trailing newline is not counted as a line, so it gets no
+// number.
+func newGutters(lines []DiffLine, opts *Options) gutters {
+	if !opts.LineNumbers {
+		return gutters{}
+	}
+	es := newEditScript(lines)
+	return gutters{
+		left:  gutter{digits: len(fmt.Sprint(es.nLeft)), last: es.nLeft},
+		right: gutter{digits: len(fmt.Sprint(es.nRight)), last: es.nRight},
+	}
+}
+
+func (g gutters) width() int {
+	return g.left.width() + g.right.width()
+}
+
+// width is the number of columns the gutter takes, including the space
+// separating it from the text.
+func (g gutter) width() int {
+	if g.digits == 0 {
+		return 0
+	}
+	return g.digits + 1
+}
+
+// shows reports whether the gutter has a number for line n.
+func (g gutter) shows(n int) bool {
+	return g.digits > 0 && n > 0 && n <= g.last
+}
+
+// format returns the gutter for line n, blank if it has no number.
+func (g gutter) format(n int, opts *Options) string {
+	if g.digits == 0 {
+		return ""
+	}
+	if !g.shows(n) {
+		return strings.Repeat(" ", g.width())
+	}
+	num := fmt.Sprintf("%*d", g.digits, n)
+	if opts.TermMode {
+		num = colorize(num, opts.theme().LineNumber)
+	}
+	return num + " "
+}
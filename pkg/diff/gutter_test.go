@@ -0,0 +1,44 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatDiffLineNumbers(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	b := "one\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+	got := Compare(a, b, LineNumbers(true))
+	expected := strings.Join([]string{
+		" 1 one   ==  1 one",
+		" 2 two   1d",
+		" 3 three ==  2 three",
+		" 4 four  ==  3 four",
+		" 5 five  ==  4 five",
+		" 6 six   ==  5 six",
+		" 7 seven ==  6 seven",
+		" 8 eight ==  7 eight",
+		" 9 nine  ==  8 nine",
+		"10 ten   ==  9 ten",
+		"         1d 10 eleven",
+		"         ==",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestFormatDiffLineNumbersWrapped(t *testing.T) {
+	got := Compare("abcdefgh\n", "abcdefgX\n", LineNumbers(true), Width(16), MarkersCaret)
+	expected := strings.Join([]string{
+		"1 abcd 1d 1 abcd",
+		"  efgh      efgX",
+		"     ^         ^",
+		"       ==",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
@@ -0,0 +1,189 @@
+package diff
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// htmlStyle is embedded in every report so that it is a single standalone file.
+const htmlStyle = `
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 1.5em; color: #1f2328; }
+h1 { font-size: 1.4em; }
+h2 { font-size: 1.1em; margin: 1.5em 0 0.5em; }
+.status { font-size: 0.8em; font-weight: normal; padding: 0.1em 0.5em; border-radius: 1em; background: #eaeef2; }
+.status.modified { background: #fff8c5; }
+.status.added { background: #dafbe1; }
+.status.deleted { background: #ffebe9; }
+table.diff { width: 100%; border-collapse: collapse; table-layout: fixed; font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 0.85em; }
+table.diff td { padding: 0 0.5em; vertical-align: top; white-space: pre-wrap; word-break: break-all; }
+col.num { width: 3.5em; }
+col.sym { width: 2.5em; }
+td.num { color: #6e7781; text-align: right; user-select: none; }
+td.sym { text-align: center; font-weight: bold; border-left: 1px solid #d0d7de; border-right: 1px solid #d0d7de; }
+tr.changed td.text { background: #fff8c5; }
+tr.removed td.left, tr.changed td.left { background: #ffebe9; }
+tr.added td.right, tr.changed td.right { background: #e6ffec; }
+del { background: #ffb3ad; text-decoration: none; }
+ins { background: #8ceba0; text-decoration: none; }
+details.fold summary { cursor: pointer; color: #0969da; background: #ddf4ff; padding: 0.1em 0.5em; font-size: 0.85em; }
+table.legend { border-collapse: collapse; font-size: 0.85em; margin-bottom: 1em; }
+table.legend td { padding: 0.1em 0.75em; border: 1px solid #d0d7de; }
+nav { margin: 0.5em 0; }
+nav a { margin-right: 1em; color: #0969da; }
+#filter { margin-bottom: 0.5em; padding: 0.25em; width: 20em; }
+table.index { border-collapse: collapse; font-size: 0.9em; }
+table.index th, table.index td { padding: 0.2em 0.75em; border-bottom: 1px solid #d0d7de; text-align: left; }
+table.index td.count { text-align: right; font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; }
+td.count.added { color: #1a7f37; }
+td.count.removed { color: #cf222e; }
+td.count.modified { color: #9a6700; }
+table.legend td:first-child { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-weight: bold; text-align: center; }
+`
+
+// symbolLegend describes the built-in DiffType symbols, in display order.
+var symbolLegend = []struct {
+	Symbol      string
+	Description string
+}{
+	{"==", "Lines are identical"},
+	{"1d", "One continuous difference block"},
+	{"2d", "Two difference blocks"},
+	{"3d…9d", "Three to nine difference blocks"},
+	{"+d", "Ten or more difference blocks"},
+	{"w", "Whitespace difference only"},
+	{"q", "Mixed character and whitespace difference"},
+	{"$", "End of line (EOL) difference"},
+	{"~", "Numbers differ within tolerance"},
+}
+
+// FormatHTML renders files as a standalone HTML page with the two sides in
+// aligned columns, line numbers, character-level highlighting and a legend of
+// the symbols used. Runs of identical lines further than opts.ContextLines
+// from a change are collapsed.
+func FormatHTML(files []*FileDiff, opts *Options) string {
+	var sb strings.Builder
+	title := "Diff"
+	if len(files) == 1 {
+		title = fmt.Sprintf("Diff %s %s", files[0].Left, files[0].Right)
+	}
+	writeHTMLHead(&sb, title)
+	writeHTMLLegend(&sb, files)
+	for _, f := range files {
+		writeHTMLFile(&sb, f, opts)
+	}
+	sb.WriteString(htmlFoot)
+	return sb.String()
+}
+
+const htmlFoot = "</body>\n</html>\n"
+
+// writeHTMLHead starts a page with the given title and the shared style sheet.
+func writeHTMLHead(sb *strings.Builder, title string) {
+	sb.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(sb, "<title>%s</title>\n<style>%s</style>\n</head>\n<body>\n", html.EscapeString(title), htmlStyle)
+	fmt.Fprintf(sb, "<h1>%s</h1>\n", html.EscapeString(title))
+}
+
+func writeHTMLLegend(sb *strings.Builder, files []*FileDiff) {
+	sb.WriteString("<table class=\"legend\">\n")
+	for _, l := range symbolLegend {
+		fmt.Fprintf(sb, "<tr><td>%s</td><td>%s</td></tr>\n", html.EscapeString(l.Symbol), l.Description)
+	}
+	custom := map[DiffType]bool{}
+	for _, f := range files {
+		for _, line := range f.Lines {
+			if !isBuiltinType(line.Type) && !custom[line.Type] {
+				custom[line.Type] = true
+				fmt.Fprintf(sb, "<tr><td>%s</td><td>Custom classification</td></tr>\n", html.EscapeString(string(line.Type)))
+			}
+		}
+	}
+	sb.WriteString("</table>\n")
+}
+
+func isBuiltinType(t DiffType) bool {
+	switch t {
+	case DiffEqual, DiffChar, DiffSpace, DiffMixed, DiffEOL, DiffTolerance, "+d":
+		return true
+	}
+	return len(t) == 2 && t[0] >= '1' && t[0] <= '9' && t[1] == 'd'
+}
+
+// writeHTMLFile writes the section for one file.
+func writeHTMLFile(sb *strings.Builder, f *FileDiff, opts *Options) {
+	fmt.Fprintf(sb, "<section class=\"file\">\n<h2>%s &rarr; %s <span class=\"status %s\">%s</span></h2>\n",
+		html.EscapeString(f.Left), html.EscapeString(f.Right), f.Status, f.Status)
+	if f.Status == StatusTypeMismatch {
+		sb.WriteString("<p>One side is a directory and the other is a regular file.</p>\n</section>\n")
+		return
+	}
+	pos := 0
+	for _, h := range Hunks(f.Lines, opts.ContextLines) {
+		writeHTMLFold(sb, f.Lines[pos:h.Start])
+		writeHTMLTable(sb, h.Lines)
+		pos = h.End()
+	}
+	writeHTMLFold(sb, f.Lines[pos:])
+	sb.WriteString("</section>\n")
+}
+
+// writeHTMLFold writes identical lines as a table that is collapsed until
+// clicked.
+func writeHTMLFold(sb *strings.Builder, lines []DiffLine) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "<details class=\"fold\"><summary>%d unchanged lines</summary>\n", len(lines))
+	writeHTMLTable(sb, lines)
+	sb.WriteString("</details>\n")
+}
+
+func writeHTMLTable(sb *strings.Builder, lines []DiffLine) {
+	sb.WriteString("<table class=\"diff\"><colgroup><col class=\"num\"><col><col class=\"sym\"><col class=\"num\"><col></colgroup>\n")
+	for _, line := range lines {
+		writeHTMLRow(sb, line)
+	}
+	sb.WriteString("</table>\n")
+}
+
+func writeHTMLRow(sb *strings.Builder, line DiffLine) {
+	class := "equal"
+	switch {
+	case line.LeftNum == 0 && line.RightNum != 0:
+		class = "added"
+	case line.RightNum == 0 && line.LeftNum != 0:
+		class = "removed"
+	case line.Type != DiffEqual:
+		class = "changed"
+	}
+
+	var left, right strings.Builder
+	if line.Type == DiffEqual || len(line.Ops) == 0 {
+		left.WriteString(html.EscapeString(line.Left))
+		right.WriteString(html.EscapeString(line.Right))
+	} else {
+		for _, op := range CoalesceOps(line.Ops) {
+			text := html.EscapeString(op.Content)
+			switch op.Type {
+			case OpMatch:
+				left.WriteString(text)
+				right.WriteString(text)
+			case OpDelete:
+				left.WriteString("<del>" + text + "</del>")
+			case OpInsert:
+				right.WriteString("<ins>" + text + "</ins>")
+			}
+		}
+	}
+
+	fmt.Fprintf(sb, "<tr class=\"%s\"><td class=\"num\">%s</td><td class=\"text left\">%s</td><td class=\"sym\">%s</td><td class=\"num\">%s</td><td class=\"text right\">%s</td></tr>\n",
+		class, lineNumber(line.LeftNum), left.String(), html.EscapeString(string(line.Type)), lineNumber(line.RightNum), right.String())
+}
+
+func lineNumber(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d", n)
+}
@@ -0,0 +1,78 @@
+package diff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatHTML(t *testing.T) {
+	output := Compare("foo\n<b>\nsame\n", "fox\n<b>\nsame\nadded\n", OutputHTML, Labels{Left: "old.txt", Right: "new.txt"})
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<style>",
+		"<title>Diff old.txt new.txt</title>",
+		`<tr class="changed"><td class="num">1</td><td class="text left">fo<del>o</del></td><td class="sym">1d</td><td class="num">1</td><td class="text right">fo<ins>x</ins></td></tr>`,
+		`<tr class="equal"><td class="num">2</td><td class="text left">&lt;b&gt;</td><td class="sym">==</td><td class="num">2</td><td class="text right">&lt;b&gt;</td></tr>`,
+		`<tr class="added"><td class="num"></td><td class="text left"></td><td class="sym">`,
+		"<td>~</td><td>Numbers differ within tolerance</td>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "<link") || strings.Contains(output, "<script") {
+		t.Errorf("Expected a standalone page without external assets:\n%s", output)
+	}
+}
+
+func TestFormatHTMLFoldsUnchangedRuns(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 20; i++ {
+		a = append(a, fmt.Sprintf("line %d", i))
+		b = append(b, fmt.Sprintf("line %d", i))
+	}
+	b[10] = "changed"
+	output := Compare(a, b, OutputHTML, ContextLines(2))
+	if got := strings.Count(output, `<details class="fold">`); got != 2 {
+		t.Errorf("Expected 2 folded regions, got %d:\n%s", got, output)
+	}
+	for _, want := range []string{"<summary>8 unchanged lines</summary>", "<summary>7 unchanged lines</summary>"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q", want)
+		}
+	}
+}
+
+func TestDiffHTML(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"dir1/a.txt": "one\n",
+		"dir2/a.txt": "two\n",
+		"dir2/b.txt": "new\n",
+	} {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output, err := Diff(filepath.Join(dir, "dir1"), filepath.Join(dir, "dir2"), OutputHTML)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if got := strings.Count(output, "<!DOCTYPE html>"); got != 1 {
+		t.Errorf("Expected a single page, got %d", got)
+	}
+	if got := strings.Count(output, `<section class="file">`); got != 2 {
+		t.Errorf("Expected 2 file sections, got %d:\n%s", got, output)
+	}
+	if !strings.Contains(output, `<span class="status added">added</span>`) {
+		t.Errorf("Expected b.txt to be marked as added:\n%s", output)
+	}
+}
@@ -0,0 +1,132 @@
+package diff
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// htmlFilterScript hides index rows that do not contain the filter text.
+const htmlFilterScript = `<script>
+document.getElementById("filter").addEventListener("input", function () {
+  var q = this.value.toLowerCase();
+  document.querySelectorAll("table.index tbody tr").forEach(function (tr) {
+    tr.style.display = tr.textContent.toLowerCase().indexOf(q) >= 0 ? "" : "none";
+  });
+});
+</script>
+`
+
+// sitePage is a file of a walk along with where it is shown in an HTML site.
+type sitePage struct {
+	file  *FileDiff
+	name  string // Path relative to the compared roots
+	page  string // Page file name, empty for unchanged files
+	stats Stats
+}
+
+// WriteHTMLSite compares path1 and path2 like Diff and writes the result to
+// dir as a set of HTML pages: index.html lists every file with its status and
+// line counts, and each changed file has its own side-by-side page linked to
+// its neighbours. The pages have no external assets.
+func WriteHTMLSite(dir, path1, path2 string, options ...interface{}) error {
+	opts := NewOptions(options...)
+
+	for _, p := range []string{path1, path2} {
+		if _, err := os.Stat(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	var pages []*sitePage
+	err := walk("", path1, path2, opts, func(f *FileDiff) error {
+		pages = append(pages, &sitePage{file: f, name: siteName(path1, path2, f), stats: f.Stats})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var changed []*sitePage
+	for _, p := range pages {
+		if p.file.Status != StatusUnchanged {
+			p.page = fmt.Sprintf("file%04d.html", len(changed)+1)
+			changed = append(changed, p)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte(siteIndex(path1, path2, pages)), 0644); err != nil {
+		return err
+	}
+	for i, p := range changed {
+		var prev, next *sitePage
+		if i > 0 {
+			prev = changed[i-1]
+		}
+		if i < len(changed)-1 {
+			next = changed[i+1]
+		}
+		if err := os.WriteFile(filepath.Join(dir, p.page), []byte(sitePageHTML(p, prev, next, opts)), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// siteName returns the path of f relative to the roots of the walk.
+func siteName(path1, path2 string, f *FileDiff) string {
+	for _, pair := range [][2]string{{path1, f.Left}, {path2, f.Right}} {
+		if rel, err := filepath.Rel(pair[0], pair[1]); err == nil && rel != "." {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.Base(f.Right)
+}
+
+func siteIndex(path1, path2 string, pages []*sitePage) string {
+	var sb strings.Builder
+	writeHTMLHead(&sb, fmt.Sprintf("Diff %s %s", path1, path2))
+	sb.WriteString("<input id=\"filter\" type=\"search\" placeholder=\"Filter files\">\n")
+	sb.WriteString("<table class=\"index\">\n<thead><tr><th>File</th><th>Status</th><th>Added</th><th>Removed</th><th>Modified</th></tr></thead>\n<tbody>\n")
+	var total Stats
+	for _, p := range pages {
+		name := html.EscapeString(p.name)
+		if p.page != "" {
+			name = fmt.Sprintf("<a href=\"%s\">%s</a>", p.page, name)
+		}
+		fmt.Fprintf(&sb, "<tr><td>%s</td><td><span class=\"status %s\">%s</span></td>%s</tr>\n",
+			name, p.file.Status, p.file.Status, statsCells(p.stats))
+		total.Add(p.stats)
+	}
+	fmt.Fprintf(&sb, "</tbody>\n<tfoot><tr><th>%d files</th><th></th>%s</tr></tfoot>\n</table>\n", len(pages), statsCells(total))
+	sb.WriteString(htmlFilterScript)
+	sb.WriteString(htmlFoot)
+	return sb.String()
+}
+
+func statsCells(s Stats) string {
+	return fmt.Sprintf("<td class=\"count added\">+%d</td><td class=\"count removed\">-%d</td><td class=\"count modified\">~%d</td>",
+		s.Added, s.Removed, s.Modified)
+}
+
+func sitePageHTML(p, prev, next *sitePage, opts *Options) string {
+	var sb strings.Builder
+	writeHTMLHead(&sb, p.name)
+	sb.WriteString("<nav><a href=\"index.html\">Index</a>")
+	if prev != nil {
+		fmt.Fprintf(&sb, "<a href=\"%s\" rel=\"prev\">&larr; %s</a>", prev.page, html.EscapeString(prev.name))
+	}
+	if next != nil {
+		fmt.Fprintf(&sb, "<a href=\"%s\" rel=\"next\">%s &rarr;</a>", next.page, html.EscapeString(next.name))
+	}
+	sb.WriteString("</nav>\n")
+	writeHTMLLegend(&sb, []*FileDiff{p.file})
+	writeHTMLFile(&sb, p.file, opts)
+	sb.WriteString(htmlFoot)
+	return sb.String()
+}
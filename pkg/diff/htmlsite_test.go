@@ -0,0 +1,81 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestComputeStats(t *testing.T) {
+	got := ComputeStats(AlignLines([]string{"keep", "old", "gone"}, []string{"keep", "olx"}, NewOptions()))
+	expected := Stats{Removed: 1, Modified: 1}
+	if got != expected {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestWriteHTMLSite(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"dir1/a.txt":     "one\n",
+		"dir2/a.txt":     "two\n",
+		"dir1/same.txt":  "same\n",
+		"dir2/same.txt":  "same\n",
+		"dir2/sub/b.txt": "new\n",
+	} {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := filepath.Join(dir, "report")
+	if err := WriteHTMLSite(out, filepath.Join(dir, "dir1"), filepath.Join(dir, "dir2")); err != nil {
+		t.Fatalf("WriteHTMLSite failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if got := strings.Join(names, " "); got != "file0001.html file0002.html index.html" {
+		t.Errorf("Unexpected report files: %s", got)
+	}
+
+	index, err := os.ReadFile(filepath.Join(out, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<a href="file0001.html">a.txt</a>`,
+		`<a href="file0002.html">sub/b.txt</a>`,
+		`<tr><td>same.txt</td><td><span class="status unchanged">unchanged</span></td>`,
+		`id="filter"`,
+		`<th>3 files</th>`,
+	} {
+		if !strings.Contains(string(index), want) {
+			t.Errorf("Expected index to contain %q:\n%s", want, index)
+		}
+	}
+
+	page, err := os.ReadFile(filepath.Join(out, "file0001.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`<a href="index.html">Index</a>`, `<a href="file0002.html" rel="next">sub/b.txt &rarr;</a>`, `<section class="file">`} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("Expected page to contain %q:\n%s", want, page)
+		}
+	}
+	if strings.Contains(string(page), `rel="prev"`) {
+		t.Errorf("Expected no previous link on the first page:\n%s", page)
+	}
+}
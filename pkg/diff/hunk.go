@@ -0,0 +1,118 @@
+package diff
+
+import "fmt"
+
+// Folding collapses runs of identical lines more than ContextLines away from
+// a change in side-by-side output into a single marker line, and leaves out
+// identical files when used with Diff.
+type Folding bool
+
+// Hunk is a run of aligned lines containing one or more changes together with
+// the identical lines of context around them. Lines between hunks are
+// identical and can be folded away.
+type Hunk struct {
+	Start int // Index in the aligned lines of the first line of the hunk
+	Lines []DiffLine
+}
+
+// End returns the index after the last line of the hunk.
+func (h Hunk) End() int {
+	return h.Start + len(h.Lines)
+}
+
+// Hunks groups the changed lines in lines into hunks with up to context
+// identical lines before and after each change. Changes separated by no more
+// than twice context identical lines share a hunk. Lines that count as equal
+// (see DiffType.IsEqual) are not changes. Identical inputs have no hunks.
+func Hunks(lines []DiffLine, context int) []Hunk {
+	var hunks []Hunk
+	for _, r := range hunkRanges(len(lines), context, func(i int) bool { return !lines[i].Type.IsEqual() }) {
+		hunks = append(hunks, Hunk{Start: r[0], Lines: lines[r[0]:r[1]]})
+	}
+	return hunks
+}
+
+// hunkRanges groups the changed items of a sequence of n into the ranges of
+// hunks, as described for Hunks. It is shared by Hunks and the patch formats,
+// which group the edits of an edit script instead of aligned lines.
+func hunkRanges(n, context int, changed func(i int) bool) [][2]int {
+	if context < 0 {
+		context = 0
+	}
+	var ranges [][2]int
+	start, end := -1, -1
+	for i := 0; i < n; i++ {
+		if !changed(i) {
+			continue
+		}
+		lo, hi := max(i-context, 0), min(i+1+context, n)
+		if start >= 0 && lo <= end {
+			end = hi
+			continue
+		}
+		if start >= 0 {
+			ranges = append(ranges, [2]int{start, end})
+		}
+		start, end = lo, hi
+	}
+	if start >= 0 {
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges
+}
+
+// sideBySideRow is a row of side-by-side output: either the line at index
+// line, or a marker for folded identical lines.
+type sideBySideRow struct {
+	line   int
+	folded int // Number of lines folded into a marker, 0 for a line
+}
+
+// sideBySideRows lists the rows to print for lines. Without Folding every
+// line is printed; with it, identical lines between hunks are replaced by a
+// marker. The empty element after a trailing newline is not counted, and runs
+// of a single line are printed rather than folded.
+func sideBySideRows(lines []DiffLine, opts *Options) []sideBySideRow {
+	var rows []sideBySideRow
+	show := func(start, end int) {
+		for i := start; i < end; i++ {
+			rows = append(rows, sideBySideRow{line: i})
+		}
+	}
+	if !opts.Folding {
+		show(0, len(lines))
+		return rows
+	}
+
+	es := newEditScript(lines)
+	fold := func(start, end int) {
+		n := 0
+		for _, line := range lines[start:end] {
+			if line.LeftNum <= es.nLeft || line.RightNum <= es.nRight {
+				n++
+			}
+		}
+		if n <= 1 {
+			show(start, end)
+			return
+		}
+		rows = append(rows, sideBySideRow{folded: n})
+	}
+	pos := 0
+	for _, h := range Hunks(lines, opts.ContextLines) {
+		fold(pos, h.Start)
+		show(h.Start, h.End())
+		pos = h.End()
+	}
+	fold(pos, len(lines))
+	return rows
+}
+
+// foldMarker is the line that replaces n folded identical lines.
+func foldMarker(n int, opts *Options) string {
+	marker := fmt.Sprintf("··· %d identical lines ···", n)
+	if opts.TermMode {
+		marker = colorize(marker, opts.theme().Header)
+	}
+	return marker + "\n"
+}
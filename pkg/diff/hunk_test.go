@@ -0,0 +1,86 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestHunks(t *testing.T) {
+	row := func(typ DiffType) DiffLine { return DiffLine{Type: typ} }
+	lines := []DiffLine{
+		row(DiffEqual), row(DiffEqual), row(DiffEqual),
+		row("1d"),
+		row(DiffEqual), row(DiffEqual),
+		row("1d"),
+		row(DiffEqual), row(DiffTolerance), row(DiffEqual), row(DiffEqual),
+		row("w"),
+	}
+	tests := []struct {
+		context  int
+		expected [][2]int
+	}{
+		{context: 1, expected: [][2]int{{2, 8}, {10, 12}}},
+		{context: 0, expected: [][2]int{{3, 4}, {6, 7}, {11, 12}}},
+		{context: 2, expected: [][2]int{{1, 12}}},
+	}
+	for _, tt := range tests {
+		var got [][2]int
+		for _, h := range Hunks(lines, tt.context) {
+			got = append(got, [2]int{h.Start, h.End()})
+		}
+		if len(got) != len(tt.expected) {
+			t.Errorf("context %d: expected %v, got %v", tt.context, tt.expected, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("context %d: expected %v, got %v", tt.context, tt.expected, got)
+				break
+			}
+		}
+	}
+
+	if hunks := Hunks([]DiffLine{row(DiffEqual)}, 3); len(hunks) != 0 {
+		t.Errorf("Expected no hunks for identical lines, got %v", hunks)
+	}
+}
+
+func TestFormatDiffFolding(t *testing.T) {
+	var a, b []string
+	for i := 1; i <= 20; i++ {
+		a = append(a, fmt.Sprintf("line %d", i))
+	}
+	b = append(b, a...)
+	b[9] = "changed"
+	got := Compare(strings.Join(a, "\n")+"\n", strings.Join(b, "\n")+"\n", Folding(true), ContextLines(2))
+	expected := strings.Join([]string{
+		"··· 7 identical lines ···",
+		"line 8  == line 8",
+		"line 9  == line 9",
+		"line 10 q  changed",
+		"line 11 == line 11",
+		"line 12 == line 12",
+		"··· 8 identical lines ···",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestFormatDiffFoldingShowsSingleLines(t *testing.T) {
+	// A single identical line is printed rather than replaced by a marker,
+	// and the empty element after the trailing newline is not a line.
+	got := Compare("a\nb\nc\n", "a\nB\nc\n", Folding(true), ContextLines(0))
+	expected := strings.Join([]string{
+		"a == a",
+		"b 1d B",
+		"c == c",
+		"  ==",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
@@ -0,0 +1,60 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ShowInvisibles renders tabs, trailing whitespace, carriage returns, NUL and
+// other control or non-printing characters as visible glyphs or escapes.
+type ShowInvisibles bool
+
+const (
+	tabGlyph           = "→"
+	trailingSpaceGlyph = "·"
+	delGlyph           = "␡"
+)
+
+// trailingSpaceStart returns the byte offset at which the trailing run of
+// spaces and tabs starts in the concatenated text of spans.
+func trailingSpaceStart(spans []span) int {
+	var sb strings.Builder
+	for _, s := range spans {
+		sb.WriteString(s.text)
+	}
+	return len(strings.TrimRight(sb.String(), " \t"))
+}
+
+// showInvisibles replaces invisible characters in text, which starts at byte
+// offset within its line. Spaces at or after trailing are shown as well.
+func showInvisibles(text string, offset, trailing int) string {
+	var sb strings.Builder
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			fmt.Fprintf(&sb, `\x%02x`, text[i])
+		case r == '\t':
+			sb.WriteString(tabGlyph)
+		case r == ' ':
+			if offset+i >= trailing {
+				sb.WriteString(trailingSpaceGlyph)
+			} else {
+				sb.WriteByte(' ')
+			}
+		case r < 0x20:
+			// Control Pictures block: U+2400 is NUL, U+240D is CR, etc.
+			sb.WriteRune(0x2400 + r)
+		case r == 0x7f:
+			sb.WriteString(delGlyph)
+		case !unicode.IsPrint(r):
+			fmt.Fprintf(&sb, `\u%04x`, r)
+		default:
+			sb.WriteRune(r)
+		}
+		i += size
+	}
+	return sb.String()
+}
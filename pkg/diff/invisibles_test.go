@@ -0,0 +1,35 @@
+package diff
+
+import "testing"
+
+func TestShowInvisibles(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"a\tb", "a→b"},
+		{"a b  ", "a b··"},
+		{"x\r", "x␍"},
+		{"\x00\x1b\x7f", "␀␛␡"},
+		{"zero\u200bwidth", `zero\u200bwidth`},
+		{"bad\xffbyte", `bad\xffbyte`},
+		{"plain", "plain"},
+	}
+	opts := NewOptions(ShowInvisibles(true))
+	for _, tt := range tests {
+		left, _ := renderDiffLine(DiffLine{Left: tt.in, Right: tt.in, Type: DiffEqual}, opts)
+		if left.text != tt.want {
+			t.Errorf("renderDiffLine(%q) = %q, want %q", tt.in, left.text, tt.want)
+		}
+	}
+}
+
+func TestShowInvisiblesTrailingAcrossOps(t *testing.T) {
+	// The trailing spaces on the right are split over several insert ops; all
+	// of them must still be recognised as trailing.
+	opts := NewOptions(ShowInvisibles(true))
+	line := AlignLines([]string{"x"}, []string{"x  "}, opts)[0]
+	_, right := renderDiffLine(line, opts)
+	if right.text != "x··" {
+		t.Errorf("Expected trailing spaces to be visible, got %q", right.text)
+	}
+}
@@ -0,0 +1,138 @@
+package diff
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JSONSchemaVersion is the version of the document produced by FormatJSON.
+// It is increased whenever a field is removed or changes meaning; new fields
+// may be added without changing it.
+const JSONSchemaVersion = 1
+
+// JSONDocument is the top level of the JSON output:
+//
+//	{
+//	  "version": 1,
+//	  "files": [
+//	    {
+//	      "left": "dir1/a.txt", "right": "dir2/a.txt",
+//	      "leftTime": "2024-01-02T03:04:05Z", "rightTime": "...",
+//	      "status": "modified",
+//	      "rows": [
+//	        {"left": "foo", "right": "fox", "leftLine": 1, "rightLine": 1, "type": "1d",
+//	         "ops": [{"op": "match", "text": "fo"}, {"op": "insert", "text": "x"}, {"op": "delete", "text": "o"}]}
+//	      ]
+//	    }
+//	  ]
+//	}
+//
+// A row's left or right text and line number are omitted when the line is
+// missing on that side. Ops are only present on rows that differ, with
+// adjacent operations of the same kind coalesced.
+type JSONDocument struct {
+	Version int        `json:"version"`
+	Files   []JSONFile `json:"files"`
+}
+
+// JSONFile is one compared file in a JSONDocument.
+type JSONFile struct {
+	Left      string     `json:"left"`
+	Right     string     `json:"right"`
+	LeftTime  *time.Time `json:"leftTime,omitempty"`
+	RightTime *time.Time `json:"rightTime,omitempty"`
+	Status    FileStatus `json:"status"`
+	Rows      []JSONRow  `json:"rows"`
+}
+
+// JSONRow is one aligned line of a JSONFile.
+type JSONRow struct {
+	Left      *string           `json:"left,omitempty"`
+	Right     *string           `json:"right,omitempty"`
+	LeftLine  int               `json:"leftLine,omitempty"`
+	RightLine int               `json:"rightLine,omitempty"`
+	Type      DiffType          `json:"type"`
+	Ops       []JSONOp          `json:"ops,omitempty"`
+	Meta      map[string]string `json:"meta,omitempty"`
+}
+
+// JSONOp is a character-level operation of a JSONRow.
+type JSONOp struct {
+	Op   string `json:"op"` // "match", "insert" or "delete"
+	Text string `json:"text"`
+}
+
+var opNames = map[OpType]string{
+	OpMatch:  "match",
+	OpInsert: "insert",
+	OpDelete: "delete",
+}
+
+// FormatJSON renders files as an indented JSONDocument.
+func FormatJSON(files []*FileDiff, opts *Options) string {
+	doc := NewJSONDocument(files)
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		// Only strings, numbers and times are marshalled, so this cannot happen.
+		panic(err)
+	}
+	return string(b) + "\n"
+}
+
+// NewJSONDocument converts files into the structure FormatJSON marshals.
+func NewJSONDocument(files []*FileDiff) JSONDocument {
+	doc := JSONDocument{Version: JSONSchemaVersion, Files: []JSONFile{}}
+	for _, f := range files {
+		doc.Files = append(doc.Files, newJSONFile(f))
+	}
+	return doc
+}
+
+func newJSONFile(f *FileDiff) JSONFile {
+	jf := JSONFile{Left: f.Left, Right: f.Right, Status: f.Status, Rows: []JSONRow{}}
+	if !f.LeftTime.IsZero() {
+		t := f.LeftTime
+		jf.LeftTime = &t
+	}
+	if !f.RightTime.IsZero() {
+		t := f.RightTime
+		jf.RightTime = &t
+	}
+	for _, line := range f.Lines {
+		row := JSONRow{
+			LeftLine:  line.LeftNum,
+			RightLine: line.RightNum,
+			Type:      line.Type,
+			Meta:      line.Meta,
+		}
+		if line.LeftNum != 0 {
+			left := line.Left
+			row.Left = &left
+		}
+		if line.RightNum != 0 {
+			right := line.Right
+			row.Right = &right
+		}
+		if line.Type != DiffEqual {
+			for _, op := range CoalesceOps(line.Ops) {
+				row.Ops = append(row.Ops, JSONOp{Op: opNames[op.Type], Text: op.Content})
+			}
+		}
+		jf.Rows = append(jf.Rows, row)
+	}
+	return jf
+}
+
+// CoalesceOps merges adjacent operations of the same type, turning the
+// per-character edit script of a DiffLine into one operation per run.
+func CoalesceOps(ops []Operation) []Operation {
+	var result []Operation
+	for _, op := range ops {
+		if n := len(result); n > 0 && result[n-1].Type == op.Type {
+			result[n-1].Content += op.Content
+			continue
+		}
+		result = append(result, op)
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package diff
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatJSONCompare(t *testing.T) {
+	output := Compare("foo\nsame\ngone\n", "fox\nsame\n", OutputJSON, Labels{Left: "old.txt", Right: "new.txt"})
+
+	var doc JSONDocument
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if doc.Version != JSONSchemaVersion {
+		t.Errorf("Expected version %d, got %d", JSONSchemaVersion, doc.Version)
+	}
+	if len(doc.Files) != 1 {
+		t.Fatalf("Expected 1 file, got %d", len(doc.Files))
+	}
+	f := doc.Files[0]
+	if f.Left != "old.txt" || f.Right != "new.txt" || f.Status != StatusModified {
+		t.Errorf("Unexpected file entry: %+v", f)
+	}
+	if f.LeftTime != nil || f.RightTime != nil {
+		t.Errorf("Timestamps should be omitted when not set")
+	}
+	if len(f.Rows) != 4 {
+		t.Fatalf("Expected 4 rows, got %d", len(f.Rows))
+	}
+
+	first := f.Rows[0]
+	expectedOps := []JSONOp{{Op: "match", Text: "fo"}, {Op: "insert", Text: "x"}, {Op: "delete", Text: "o"}}
+	if len(first.Ops) != len(expectedOps) {
+		t.Fatalf("Expected coalesced ops %v, got %v", expectedOps, first.Ops)
+	}
+	for i := range expectedOps {
+		if first.Ops[i] != expectedOps[i] {
+			t.Errorf("Op %d: expected %v, got %v", i, expectedOps[i], first.Ops[i])
+		}
+	}
+
+	if f.Rows[1].Ops != nil || f.Rows[1].Type != DiffEqual {
+		t.Errorf("Equal rows should have no ops: %+v", f.Rows[1])
+	}
+
+	gone := f.Rows[2]
+	if gone.Left == nil || *gone.Left != "gone" || gone.LeftLine != 3 {
+		t.Errorf("Deleted row should keep its left side: %+v", gone)
+	}
+	if gone.Right != nil || gone.RightLine != 0 {
+		t.Errorf("Deleted row should have no right side: %+v", gone)
+	}
+}
+
+func TestFormatJSONDiff(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"dir1/same.txt": "same\n",
+		"dir2/same.txt": "same\n",
+		"dir2/new.txt":  "new\n",
+	} {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output, err := Diff(filepath.Join(dir, "dir1"), filepath.Join(dir, "dir2"), OutputJSON)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	var doc JSONDocument
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(doc.Files) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(doc.Files))
+	}
+	if doc.Files[0].Status != StatusAdded || doc.Files[0].RightTime == nil {
+		t.Errorf("Expected new.txt to be added with a timestamp: %+v", doc.Files[0])
+	}
+	if doc.Files[1].Status != StatusUnchanged {
+		t.Errorf("Expected same.txt to be unchanged: %+v", doc.Files[1])
+	}
+}
@@ -0,0 +1,82 @@
+package diff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+// FormatJUnit renders files as a JUnit XML report for CI systems, with a
+// test case for every compared file. Files that differ are failures whose
+// body is a unified diff of the file, with opts.ContextLines lines of
+// context. Rows within Tolerance do not fail a file and are shown as context.
+func FormatJUnit(files []*FileDiff, opts *Options) string {
+	suite := junitTestSuite{Name: "diff"}
+	for _, f := range files {
+		tc := junitTestCase{Name: statName(f), ClassName: "diff"}
+		if f.Status != StatusUnchanged {
+			tc.Failure = &junitFailure{
+				Message: junitMessage(f),
+				Type:    string(f.Status),
+				Text:    junitBody(f, opts),
+			}
+			suite.Failures++
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+	}
+	doc := junitTestSuites{Tests: suite.Tests, Failures: suite.Failures, Suites: []junitTestSuite{suite}}
+	b, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		// Only strings and numbers are marshalled, so this cannot happen.
+		panic(err)
+	}
+	return xml.Header + string(b) + "\n"
+}
+
+// junitMessage summarises how f differs.
+func junitMessage(f *FileDiff) string {
+	if f.Status == StatusTypeMismatch {
+		return "one side is a directory and the other is a regular file"
+	}
+	s := f.Stats
+	return fmt.Sprintf("%s: %d added, %d removed, %d modified", f.Status, s.Added, s.Removed, s.Modified)
+}
+
+// junitBody is the unified diff of f, without colours.
+func junitBody(f *FileDiff, opts *Options) string {
+	if f.Status == StatusTypeMismatch {
+		return typeMismatch(f)
+	}
+	fileOpts := *opts
+	fileOpts.TermMode = false
+	fileOpts.Labels = Labels{Left: f.Left, Right: f.Right}
+	fileOpts.Timestamps = Timestamps{Left: f.LeftTime, Right: f.RightTime}
+	return strings.TrimSuffix(FormatUnified(f.Lines, &fileOpts), "\n")
+}
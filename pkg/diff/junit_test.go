@@ -0,0 +1,80 @@
+package diff
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestFormatJUnit(t *testing.T) {
+	t.Chdir(writeStatTree(t))
+	got, err := Diff("dir1", "dir2", OutputJUnit, ContextLines(0))
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	var doc junitTestSuites
+	if err := xml.Unmarshal([]byte(got), &doc); err != nil {
+		t.Fatalf("Invalid XML: %v\n%s", err, got)
+	}
+	if doc.Tests != 4 || doc.Failures != 3 || len(doc.Suites) != 1 {
+		t.Fatalf("Unexpected counts in:\n%s", got)
+	}
+	cases := doc.Suites[0].Cases
+	if cases[1].Name != "same.txt" || cases[1].Failure != nil {
+		t.Errorf("Expected same.txt to pass, got %+v", cases[1])
+	}
+	f := cases[0].Failure
+	if cases[0].Name != "mod.txt" || f == nil {
+		t.Fatalf("Expected mod.txt to fail, got %+v", cases[0])
+	}
+	if expected := "modified: 1 added, 0 removed, 1 modified"; f.Message != expected {
+		t.Errorf("Expected message %q, got %q", expected, f.Message)
+	}
+	body := f.Text[strings.Index(f.Text, "@@"):]
+	if expected := "@@ -2 +2 @@\n-b\n+B\n@@ -3,0 +4 @@\n+d"; body != expected {
+		t.Errorf("Expected body:\n%s\nGot:\n%s", expected, body)
+	}
+	if !strings.HasPrefix(f.Text, "--- dir1/mod.txt\t") {
+		t.Errorf("Expected a unified diff header, got:\n%s", f.Text)
+	}
+}
+
+func TestFormatJUnitCDATA(t *testing.T) {
+	got := Compare("x", "a]]>b", OutputJUnit, TermMode(true))
+	var doc junitTestSuites
+	if err := xml.Unmarshal([]byte(got), &doc); err != nil {
+		t.Fatalf("Invalid XML: %v\n%s", err, got)
+	}
+	if text := doc.Suites[0].Cases[0].Failure.Text; !strings.HasSuffix(text, "-x\n\\ No newline at end of file\n+a]]>b\n\\ No newline at end of file") {
+		t.Errorf("Unexpected failure body:\n%q", text)
+	}
+}
+
+func TestFormatJUnitTolerance(t *testing.T) {
+	var doc junitTestSuites
+	got := Compare("x 1.000\ny\n", "x 1.001\ny\n", OutputJUnit, Tolerance{Abs: 0.01})
+	if err := xml.Unmarshal([]byte(got), &doc); err != nil {
+		t.Fatalf("Invalid XML: %v\n%s", err, got)
+	}
+	if doc.Tests != 1 || doc.Failures != 0 {
+		t.Errorf("Expected a passing test within tolerance, got:\n%s", got)
+	}
+
+	got = Compare("x 1.000\ny\n", "x 1.001\nz\n", OutputJUnit, Tolerance{Abs: 0.01})
+	doc = junitTestSuites{}
+	if err := xml.Unmarshal([]byte(got), &doc); err != nil {
+		t.Fatalf("Invalid XML: %v\n%s", err, got)
+	}
+	f := doc.Suites[0].Cases[0].Failure
+	if f == nil {
+		t.Fatalf("Expected a failure, got:\n%s", got)
+	}
+	if expected := "modified: 0 added, 0 removed, 1 modified"; f.Message != expected {
+		t.Errorf("Expected message %q, got %q", expected, f.Message)
+	}
+	body := f.Text[strings.Index(f.Text, "@@"):]
+	if expected := "@@ -1,2 +1,2 @@\n x 1.001\n-y\n+z"; body != expected {
+		t.Errorf("Expected body:\n%s\nGot:\n%s", expected, body)
+	}
+}
@@ -0,0 +1,98 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatMarkdown renders files as Markdown for code review comments: a table
+// summarising the changed files followed by a fenced diff block of unified
+// hunks for each one. Hunks have opts.ContextLines lines of context, and
+// blocks longer than opts.MaxDiffLines are cut short with a note of how many
+// lines were left out.
+func FormatMarkdown(files []*FileDiff, opts *Options) string {
+	var changed []*FileDiff
+	for _, f := range files {
+		if f.Status != StatusUnchanged {
+			changed = append(changed, f)
+		}
+	}
+	if len(changed) == 0 {
+		return "No differences.\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("| File | Status | Added | Removed |\n| --- | --- | ---: | ---: |\n")
+	scripts := make([]*editScript, len(changed))
+	for i, f := range changed {
+		scripts[i] = newEditScript(f.Lines)
+		added, removed := scripts[i].counts()
+		fmt.Fprintf(&sb, "| %s | %s | +%d | -%d |\n", markdownName(f), f.Status, added, removed)
+	}
+
+	for i, f := range changed {
+		fmt.Fprintf(&sb, "\n### %s\n\n", markdownName(f))
+		if f.Status == StatusTypeMismatch {
+			sb.WriteString("One side is a directory and the other is a regular file.\n")
+			continue
+		}
+		lines := unifiedHunkLines(scripts[i], scripts[i].hunks(opts.ContextLines))
+		omitted := 0
+		if opts.MaxDiffLines > 0 && len(lines) > opts.MaxDiffLines {
+			omitted = len(lines) - opts.MaxDiffLines
+			lines = lines[:opts.MaxDiffLines]
+		}
+		fence := markdownFence(lines)
+		sb.WriteString(fence + "diff\n")
+		for _, line := range lines {
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString(fence + "\n")
+		if omitted > 0 {
+			fmt.Fprintf(&sb, "\n_%d more lines not shown._\n", omitted)
+		}
+	}
+	return sb.String()
+}
+
+// counts returns the number of inserted and deleted lines.
+func (es *editScript) counts() (inserted, deleted int) {
+	for _, e := range es.edits {
+		switch e.kind {
+		case editInsert:
+			inserted++
+		case editDelete:
+			deleted++
+		}
+	}
+	return inserted, deleted
+}
+
+// markdownName formats the names of f as code spans for a heading or table.
+func markdownName(f *FileDiff) string {
+	name := func(s string) string {
+		return "`" + strings.ReplaceAll(s, "|", `\|`) + "`"
+	}
+	if f.Left == f.Right {
+		return name(f.Left)
+	}
+	return name(f.Left) + " → " + name(f.Right)
+}
+
+// markdownFence returns a code fence longer than any run of backticks that
+// starts one of lines (after indentation), so that the block cannot be closed
+// early.
+func markdownFence(lines []string) string {
+	longest := 0
+	for _, line := range lines {
+		line = strings.TrimLeft(line, " ")
+		n := len(line) - len(strings.TrimLeft(line, "`"))
+		if n > longest {
+			longest = n
+		}
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
@@ -0,0 +1,91 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatMarkdown(t *testing.T) {
+	got := Compare("one\ntwo\nthree\n", "one\nTWO\nthree\nfour\n", OutputMarkdown, Labels{Left: "old.txt", Right: "new.txt"}, ContextLines(1))
+	expected := "| File | Status | Added | Removed |\n" +
+		"| --- | --- | ---: | ---: |\n" +
+		"| `old.txt` → `new.txt` | modified | +2 | -1 |\n" +
+		"\n" +
+		"### `old.txt` → `new.txt`\n" +
+		"\n" +
+		"```diff\n" +
+		"@@ -1,3 +1,4 @@\n" +
+		" one\n" +
+		"-two\n" +
+		"+TWO\n" +
+		" three\n" +
+		"+four\n" +
+		"```\n"
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestFormatMarkdownTruncates(t *testing.T) {
+	got := Compare("a\nb\nc\n", "x\ny\nz\n", OutputMarkdown, MaxDiffLines(3))
+	expected := "| File | Status | Added | Removed |\n" +
+		"| --- | --- | ---: | ---: |\n" +
+		"| `a` → `b` | modified | +3 | -3 |\n" +
+		"\n" +
+		"### `a` → `b`\n" +
+		"\n" +
+		"```diff\n" +
+		"@@ -1,3 +1,3 @@\n" +
+		"-a\n" +
+		"-b\n" +
+		"```\n" +
+		"\n" +
+		"_4 more lines not shown._\n"
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestFormatMarkdownFence(t *testing.T) {
+	got := Compare("```\nold\n", "```\nnew\n", OutputMarkdown, Labels{Left: "x.md", Right: "x.md"})
+	expected := "| File | Status | Added | Removed |\n" +
+		"| --- | --- | ---: | ---: |\n" +
+		"| `x.md` | modified | +1 | -1 |\n" +
+		"\n" +
+		"### `x.md`\n" +
+		"\n" +
+		"````diff\n" +
+		"@@ -1,2 +1,2 @@\n" +
+		" ```\n" +
+		"-old\n" +
+		"+new\n" +
+		"````\n"
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestDiffMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"dir1/same.txt": "same\n",
+		"dir2/same.txt": "same\n",
+	} {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := Diff(filepath.Join(dir, "dir1"), filepath.Join(dir, "dir2"), OutputMarkdown)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if got != "No differences.\n" {
+		t.Errorf("Expected no differences, got:\n%s", got)
+	}
+}
@@ -0,0 +1,111 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatNormal renders lines in the "normal" format of diff with no options:
+// change commands such as 3c3 or 5a6,7 followed by the affected lines marked
+// with < and >. Identical inputs produce no output.
+func FormatNormal(lines []DiffLine, opts *Options) string {
+	es := newEditScript(lines)
+	var sb strings.Builder
+	for _, h := range es.hunks(0) {
+		var deleted, inserted []edit
+		for _, e := range h.edits {
+			switch e.kind {
+			case editDelete:
+				deleted = append(deleted, e)
+			case editInsert:
+				inserted = append(inserted, e)
+			}
+		}
+
+		var command string
+		switch {
+		case len(inserted) == 0:
+			command = normalRange(h.leftStart, h.leftCount) + "d" + fmt.Sprintf("%d", h.rightStart)
+		case len(deleted) == 0:
+			command = fmt.Sprintf("%d", h.leftStart) + "a" + normalRange(h.rightStart, h.rightCount)
+		default:
+			command = normalRange(h.leftStart, h.leftCount) + "c" + normalRange(h.rightStart, h.rightCount)
+		}
+		sb.WriteString(styleLine(command, opts.theme().Header, opts))
+
+		for _, e := range deleted {
+			sb.WriteString(styleLine("< "+e.text, opts.theme().Deleted, opts))
+			if es.missingEOL(e) {
+				sb.WriteString(noEOLMarker + "\n")
+			}
+		}
+		if len(deleted) > 0 && len(inserted) > 0 {
+			sb.WriteString("---\n")
+		}
+		for _, e := range inserted {
+			sb.WriteString(styleLine("> "+e.text, opts.theme().Inserted, opts))
+			if es.missingEOL(e) {
+				sb.WriteString(noEOLMarker + "\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// FormatEd renders lines as an ed script (diff -e) that turns the left input
+// into the right one. Commands are listed from the end of the file backwards
+// so that earlier line numbers stay valid. Like diff -e, a missing newline at
+// the end of either input cannot be represented and is ignored.
+func FormatEd(lines []DiffLine, opts *Options) string {
+	es := newEditScript(lines)
+	hunks := es.hunks(0)
+	var sb strings.Builder
+	for i := len(hunks) - 1; i >= 0; i-- {
+		h := hunks[i]
+		var inserted []string
+		for _, e := range h.edits {
+			if e.kind == editInsert {
+				inserted = append(inserted, e.text)
+			}
+		}
+
+		switch {
+		case len(inserted) == 0:
+			sb.WriteString(normalRange(h.leftStart, h.leftCount) + "d\n")
+			continue
+		case h.leftCount == 0:
+			sb.WriteString(fmt.Sprintf("%da\n", h.leftStart))
+		default:
+			sb.WriteString(normalRange(h.leftStart, h.leftCount) + "c\n")
+		}
+		writeEdText(&sb, inserted)
+	}
+	return sb.String()
+}
+
+// writeEdText writes the text for an a or c command. A line consisting of a
+// single "." would end the input early, so it is written as ".." and fixed up
+// with a substitution before appending the rest.
+func writeEdText(sb *strings.Builder, lines []string) {
+	for i, line := range lines {
+		if line != "." {
+			sb.WriteString(line + "\n")
+			continue
+		}
+		sb.WriteString("..\n.\ns/.//\n")
+		if i == len(lines)-1 {
+			return
+		}
+		sb.WriteString("a\n")
+	}
+	sb.WriteString(".\n")
+}
+
+// normalRange formats the lines after before as "first,last", or just the line
+// number for a single line.
+func normalRange(before, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, before+count)
+}
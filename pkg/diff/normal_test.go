@@ -0,0 +1,51 @@
+package diff
+
+import "testing"
+
+func TestFormatNormal(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected string
+	}{
+		{"identical", "a\n", "a\n", ""},
+		{"change and append", "a\nb\nc\n", "a\n.\nx\n.\nc\nd\n", "2c2,4\n< b\n---\n> .\n> x\n> .\n3a6\n> d\n"},
+		{"delete", "a\nb\nc\nd\n", "a\n", "2,4d1\n< b\n< c\n< d\n"},
+		{"no newline", "x\ny", "x\nz", "2c2\n< y\n\\ No newline at end of file\n---\n> z\n\\ No newline at end of file\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(tt.a, tt.b, OutputNormal)
+			if got != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatEd(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected string
+	}{
+		{"identical", "a\n", "a\n", ""},
+		{"delete", "a\nb\nc\nd\n", "a\n", "2,4d\n"},
+		{"append", "a\n", "a\nb\nc\n", "1a\nb\nc\n.\n"},
+		{
+			// Commands run from the bottom up, and lone dots are escaped.
+			name:     "change with dots",
+			a:        "a\nb\nc\n",
+			b:        "a\n.\nx\n.\nc\nd\n",
+			expected: "3a\nd\n.\n2c\n..\n.\ns/.//\na\nx\n..\n.\ns/.//\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(tt.a, tt.b, OutputEd)
+			if got != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
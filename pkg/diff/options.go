@@ -1,10 +1,50 @@
 package diff
 
+import "time"
+
 type TermMode bool
 type Interactive bool
 type MaxLines int
 type LineUpFunc func(a, b []string, opts *Options) []DiffLine
 type FileFilter func(path string) bool
+type ContextLines int
+
+// OutputFormat selects how Compare and Diff render their results.
+type OutputFormat string
+
+const (
+	OutputSideBySide OutputFormat = "side-by-side"
+	OutputUnified    OutputFormat = "unified"
+	OutputContext    OutputFormat = "context"
+	OutputNormal     OutputFormat = "normal"
+	OutputEd         OutputFormat = "ed"
+	OutputJSON       OutputFormat = "json"
+	OutputHTML       OutputFormat = "html"
+	OutputMarkdown   OutputFormat = "markdown"
+	OutputStat       OutputFormat = "stat"
+	OutputWordDiff   OutputFormat = "word-diff"
+	OutputJUnit      OutputFormat = "junit"
+	OutputGitHub     OutputFormat = "github"
+	OutputQuickfix   OutputFormat = "quickfix"
+)
+
+// MaxDiffLines limits the number of diff lines shown for each file in
+// Markdown output. 0 means no limit.
+type MaxDiffLines int
+
+// Labels names the two inputs in formats that have file headers. Diff fills
+// them in with the paths being compared.
+type Labels struct {
+	Left  string
+	Right string
+}
+
+// Timestamps are the modification times shown next to Labels. Zero times are
+// omitted.
+type Timestamps struct {
+	Left  time.Time
+	Right time.Time
+}
 
 type TestingT interface {
 	Helper()
@@ -12,26 +52,64 @@ type TestingT interface {
 }
 
 type Options struct {
-	TermMode    bool
-	Interactive bool
-	MaxLines    int
-	LineUpFunc  LineUpFunc
-	TestingT    TestingT
-	FileFilter  FileFilter
+	TermMode       bool
+	Interactive    bool
+	MaxLines       int
+	LineUpFunc     LineUpFunc
+	TestingT       TestingT
+	FileFilter     FileFilter
+	Classifier     Classifier
+	Tolerance      *Tolerance
+	Scrubbers      Scrubbers
+	ShowOriginal   bool
+	ShowInvisibles bool
+	Markers        Markers
+	Format         OutputFormat
+	Renderer       Renderer
+	Stat           bool
+	ContextLines   int
+	MaxDiffLines   int
+	TabWidth       int
+	Width          int
+	Overflow       Overflow
+	Layout         Layout
+	LineNumbers    bool
+	Folding        bool
+	Theme          *Theme
+	Labels         Labels
+	Timestamps     Timestamps
+
+	// walk is set while rendering the files of Diff, whose formats name each
+	// file, rather than the single input of Compare.
+	walk bool
+}
+
+// Walking reports whether the options are those of a Diff run, for
+// renderers that name each file or report files that are a directory on the
+// other side, rather than of the single input of Compare.
+func (o *Options) Walking() bool {
+	return o.walk
 }
 
 type DiffType string
 
 const (
-	DiffEqual DiffType = "=="
-	Diff1     DiffType = "1d" // One continuous difference
-	Diff2     DiffType = "2d" // Two differences
-	DiffChar  DiffType = "d"  // Generic difference (Legacy, used for 3+)
-	DiffSpace DiffType = "w"  // Whitespace only
-	DiffMixed DiffType = "q"  // Character and whitespace
-	DiffEOL   DiffType = "$"  // EOL difference
+	DiffEqual     DiffType = "=="
+	Diff1         DiffType = "1d" // One continuous difference
+	Diff2         DiffType = "2d" // Two differences
+	DiffChar      DiffType = "d"  // Generic difference (Legacy, used for 3+)
+	DiffSpace     DiffType = "w"  // Whitespace only
+	DiffMixed     DiffType = "q"  // Character and whitespace
+	DiffEOL       DiffType = "$"  // EOL difference
+	DiffTolerance DiffType = "~"  // Numbers differ within Tolerance
 )
 
+// IsEqual reports whether lines of this type count as matching, i.e. they are
+// identical or only differ within the configured Tolerance.
+func (t DiffType) IsEqual() bool {
+	return t == DiffEqual || t == DiffTolerance
+}
+
 type OpType int
 
 const (
@@ -40,6 +118,11 @@ const (
 	OpDelete
 )
 
+// String returns "match", "insert" or "delete".
+func (t OpType) String() string {
+	return opNames[t]
+}
+
 type Operation struct {
 	Type    OpType
 	Content string
@@ -50,11 +133,19 @@ type DiffLine struct {
 	Right string
 	Type  DiffType
 	Ops   []Operation
+	Meta  map[string]string
+	// LeftNum and RightNum are the 1-based line numbers of Left and Right in
+	// their inputs, or 0 if that side has no line (an insertion or deletion).
+	LeftNum  int
+	RightNum int
 }
 
 func NewOptions(args ...interface{}) *Options {
 	opts := &Options{
-		MaxLines: 1000,
+		MaxLines:     1000,
+		ContextLines: 3,
+		TabWidth:     defaultTabWidth,
+		Labels:       Labels{Left: "a", Right: "b"},
 	}
 	for _, arg := range args {
 		switch v := arg.(type) {
@@ -75,6 +166,48 @@ func NewOptions(args ...interface{}) *Options {
 			opts.TestingT = v
 		case FileFilter:
 			opts.FileFilter = v
+		case Tolerance:
+			opts.Tolerance = &v
+		case Classifier:
+			opts.Classifier = v
+		case Scrubber:
+			opts.Scrubbers = append(opts.Scrubbers, v)
+		case Scrubbers:
+			opts.Scrubbers = append(opts.Scrubbers, v...)
+		case ShowOriginal:
+			opts.ShowOriginal = bool(v)
+		case ShowInvisibles:
+			opts.ShowInvisibles = bool(v)
+		case Markers:
+			opts.Markers = v
+		case OutputFormat:
+			opts.Format = v
+		case Renderer:
+			opts.Renderer = v
+		case Stat:
+			opts.Stat = bool(v)
+		case ContextLines:
+			opts.ContextLines = int(v)
+		case MaxDiffLines:
+			opts.MaxDiffLines = int(v)
+		case TabWidth:
+			opts.TabWidth = int(v)
+		case Width:
+			opts.Width = int(v)
+		case Overflow:
+			opts.Overflow = v
+		case Layout:
+			opts.Layout = v
+		case LineNumbers:
+			opts.LineNumbers = bool(v)
+		case Folding:
+			opts.Folding = bool(v)
+		case Theme:
+			opts.Theme = &v
+		case Labels:
+			opts.Labels = v
+		case Timestamps:
+			opts.Timestamps = v
 		}
 	}
 	return opts
@@ -92,58 +92,43 @@ func processBlock(path string, lines []string) error {
 
 	// 1. Determine separator index (maxLeft)
 	separators := map[string]bool{
-		" == ": true, " 1d ": true, " 2d ": true, " 3d ": true, " 4d ": true, " 5d ": true, " 6d ": true, " 7d ": true, " 8d ": true, " 9d ": true, " +d ": true, " d  ": true, " w  ": true, " q  ": true, " $  ": true,
+		" == ": true, " 1d ": true, " 2d ": true, " 3d ": true, " 4d ": true, " 5d ": true, " 6d ": true, " 7d ": true, " 8d ": true, " 9d ": true, " +d ": true, " d  ": true, " w  ": true, " q  ": true, " $  ": true, " ~  ": true,
 		// Trimmed versions (when right side is empty)
-		" ==": true, " 1d": true, " 2d": true, " 3d": true, " 4d": true, " 5d": true, " 6d": true, " 7d": true, " 8d": true, " 9d": true, " +d": true, " d": true, " w": true, " q": true, " $": true,
+		" ==": true, " 1d": true, " 2d": true, " 3d": true, " 4d": true, " 5d": true, " 6d": true, " 7d": true, " 8d": true, " 9d": true, " +d": true, " d": true, " w": true, " q": true, " $": true, " ~": true,
 	}
 
-	// Find consistent separator index
+	// Find consistent separator column. FormatDiff pads the left side by
+	// display width, so the separator is at the same column on every line but
+	// at a different byte offset on lines with wide or multi-byte characters.
 	// Candidates from first line
 	firstLine := lines[0]
 	var candidates []int
-	// Check for separators of length 4, 3, or 2
 	for i := 0; i <= len(firstLine)-2; i++ {
-		matched := false
-		if i+4 <= len(firstLine) && separators[firstLine[i:i+4]] {
-			matched = true
-		} else if i+3 <= len(firstLine) && separators[firstLine[i:i+3]] {
-			matched = true
-		} else if i+2 <= len(firstLine) && separators[firstLine[i:i+2]] {
-			matched = true
-		}
-		if matched {
-			candidates = append(candidates, i)
+		if separatorLen(separators, firstLine, i) > 0 {
+			candidates = append(candidates, displayWidth(firstLine[:i]))
 		}
 	}
 
-	validIdx := -1
-	for _, idx := range candidates {
+	validCol := -1
+	for _, col := range candidates {
 		isValid := true
 		for _, line := range lines {
 			if len(line) == 0 {
 				continue
 			}
-			// Check if line matches any separator at idx
-			matched := false
-			if len(line) >= idx+4 && separators[line[idx:idx+4]] {
-				matched = true
-			} else if len(line) >= idx+3 && separators[line[idx:idx+3]] {
-				matched = true
-			} else if len(line) >= idx+2 && separators[line[idx:idx+2]] {
-				matched = true
-			}
-			if !matched {
+			// Check if line matches any separator at col
+			if separatorLen(separators, line, columnOffset(line, col)) == 0 {
 				isValid = false
 				break
 			}
 		}
 		if isValid {
-			validIdx = idx
+			validCol = col
 			break
 		}
 	}
 
-	if validIdx == -1 {
+	if validCol == -1 {
 		return fmt.Errorf("could not parse diff block for %s: separator not found or inconsistent", path)
 	}
 
@@ -155,29 +140,17 @@ func processBlock(path string, lines []string) error {
 			continue
 		}
 
-		sepLen := 0
-		if len(line) >= validIdx+4 && separators[line[validIdx:validIdx+4]] {
-			sepLen = 4
-		} else if len(line) >= validIdx+3 && separators[line[validIdx:validIdx+3]] {
-			sepLen = 3
-		} else if len(line) >= validIdx+2 && separators[line[validIdx:validIdx+2]] {
-			sepLen = 2
-		}
+		idx := columnOffset(line, validCol)
+		sepLen := separatorLen(separators, line, idx)
 
 		// Should be safe given validation
 		if sepLen == 0 {
 			continue
 		}
 
-		sub := line[validIdx : validIdx+sepLen]
+		sub := line[idx : idx+sepLen]
 		sym := strings.TrimSpace(sub)
-
-		var right string
-		if len(line) >= validIdx+sepLen {
-			right = line[validIdx+sepLen:]
-		} else {
-			right = ""
-		}
+		right := line[idx+sepLen:]
 
 		// Logic:
 		// If sym != "==" (meaning diff) AND right is empty -> Skip (Deletion)
@@ -199,3 +172,17 @@ func processBlock(path string, lines []string) error {
 	data := strings.Join(content, "\n")
 	return os.WriteFile(path, []byte(data), 0644)
 }
+
+// separatorLen returns the length of the separator starting at byte idx of
+// line, preferring the longest match, or 0 if there is none.
+func separatorLen(separators map[string]bool, line string, idx int) int {
+	if idx < 0 {
+		return 0
+	}
+	for _, n := range []int{4, 3, 2} {
+		if idx+n <= len(line) && separators[line[idx:idx+n]] {
+			return n
+		}
+	}
+	return 0
+}
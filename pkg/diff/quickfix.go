@@ -0,0 +1,109 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// FormatQuickfix renders files for an editor's quickfix list, with one line
+// for each change in the form
+//
+//	path:line:col: kind: summary
+//
+// where kind is added, removed or modified, the last also for a file that is
+// a directory on the other side. Like FormatGitHub it points at the right
+// file and its line numbers, or at the left file of a deleted file, and rows
+// within Tolerance are not reported.
+// Removed lines are reported at the line before them. The column is the first
+// changed character of the first changed line, counting from 1 in runes.
+func FormatQuickfix(files []*FileDiff, opts *Options) string {
+	var sb strings.Builder
+	for _, f := range files {
+		path := annotationPath(f)
+		switch f.Status {
+		case StatusUnchanged:
+			continue
+		case StatusTypeMismatch:
+			fmt.Fprintf(&sb, "%s:1:1: modified: %s", path, typeMismatch(f))
+			continue
+		}
+		num := func(line DiffLine) int { return line.RightNum }
+		if f.Status == StatusDeleted {
+			num = func(line DiffLine) int { return line.LeftNum }
+		}
+		es := newEditScript(f.Lines)
+		for _, h := range Hunks(f.Lines, 0) {
+			var changed []DiffLine
+			for _, line := range h.Lines {
+				if !es.isTerminator(line) {
+					changed = append(changed, line)
+				}
+			}
+			if len(changed) == 0 {
+				continue
+			}
+			lineNum := num(changed[0])
+			if lineNum == 0 {
+				// Removed lines have no number; use the line before them.
+				for i := h.Start - 1; i >= 0 && lineNum == 0; i-- {
+					lineNum = num(f.Lines[i])
+				}
+				lineNum = max(lineNum, 1)
+			}
+			kind, summary := quickfixSummary(changed)
+			fmt.Fprintf(&sb, "%s:%d:%d: %s: %s\n", path, lineNum, firstChangedColumn(changed[0]), kind, summary)
+		}
+	}
+	return sb.String()
+}
+
+// quickfixSummary describes a hunk of changed lines by their kind and number,
+// followed by the text of the first one.
+func quickfixSummary(lines []DiffLine) (kind, summary string) {
+	var added, removed int
+	for _, line := range lines {
+		switch {
+		case line.LeftNum == 0:
+			added++
+		case line.RightNum == 0:
+			removed++
+		}
+	}
+	plural := func(n int, verb string) string {
+		if n == 1 {
+			return "1 line " + verb
+		}
+		return fmt.Sprintf("%d lines %s", n, verb)
+	}
+	first := lines[0]
+	switch {
+	case added == len(lines):
+		return "added", plural(added, "added") + fmt.Sprintf(": %q", first.Right)
+	case removed == len(lines):
+		return "removed", plural(removed, "removed") + fmt.Sprintf(": %q", first.Left)
+	}
+	var text string
+	switch {
+	case first.LeftNum == 0:
+		text = fmt.Sprintf("%q", first.Right)
+	case first.RightNum == 0:
+		text = fmt.Sprintf("%q", first.Left)
+	default:
+		text = fmt.Sprintf("%q -> %q", first.Left, first.Right)
+	}
+	return "modified", plural(len(lines), "changed") + ": " + text
+}
+
+// firstChangedColumn is the 1-based column, in runes, of the first character
+// of line that differs, from its Ops. Lines without Ops differ from the start.
+func firstChangedColumn(line DiffLine) int {
+	col := 1
+	for _, op := range line.Ops {
+		if op.Type != OpMatch {
+			break
+		}
+		col += utf8.RuneCountInString(op.Content)
+	}
+	return col
+}
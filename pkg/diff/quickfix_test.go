@@ -0,0 +1,72 @@
+package diff
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFormatQuickfix(t *testing.T) {
+	t.Chdir(writeStatTree(t))
+	got, err := Diff("dir1", "dir2", OutputQuickfix)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	expected := strings.Join([]string{
+		`dir2/mod.txt:2:1: modified: 1 line changed: "b" -> "B"`,
+		`dir2/mod.txt:4:1: added: 1 line added: "d"`,
+		`dir2/sub/new.txt:1:1: added: 40 lines added: "line 1"`,
+		`dir1/sub/old.txt:1:1: removed: 1 line removed: "gone"`,
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestFormatQuickfixColumns(t *testing.T) {
+	got := Compare("one\nthe cät sat\ntwo\nthree\n", "one\nthe dög sat\nnew\ntwo\n", OutputQuickfix, Labels{Left: "old.txt", Right: "new.txt"})
+	expected := strings.Join([]string{
+		`new.txt:2:5: modified: 2 lines changed: "the cät sat" -> "the dög sat"`,
+		`new.txt:4:1: removed: 1 line removed: "three"`,
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+
+	// Without Labels the right side is named b.
+	got = Compare("a\nb\n", "a\nc\n", OutputQuickfix, Labels{})
+	if expected := "b:2:1: modified: 1 line changed: \"b\" -> \"c\"\n"; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	if got := Compare("x 1.000\n", "x 1.001\n", OutputQuickfix, Tolerance{Abs: 0.01}); got != "" {
+		t.Errorf("Expected no output within tolerance, got %q", got)
+	}
+
+	if got := Compare("same\n", "same\n", OutputQuickfix); got != "" {
+		t.Errorf("Expected no output for identical inputs, got %q", got)
+	}
+}
+
+func TestFormatQuickfixTypeMismatch(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll("dir1/x", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll("dir2", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("dir2/x", []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Diff("dir1", "dir2", OutputQuickfix)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	expected := "dir2/x:1:1: modified: File dir1/x is a directory while file dir2/x is a regular file\n"
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
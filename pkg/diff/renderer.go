@@ -0,0 +1,240 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Renderer writes the results of Compare or Diff in an output format. For a
+// run, BeginRun is called once, then for each file BeginFile, Row for every
+// aligned line of the file and EndFile, and finally EndRun. Compare runs over
+// a single file named by Labels.
+//
+// The options passed to the file methods have Labels and Timestamps set for
+// that file, and Options.Walking reports whether the run is a Diff.
+// Renderers that need the whole file at once, for example to size columns,
+// can collect the rows or use the Lines of the FileDiff.
+type Renderer interface {
+	BeginRun(w io.Writer, opts *Options) error
+	BeginFile(w io.Writer, f *FileDiff, opts *Options) error
+	Row(w io.Writer, line DiffLine, opts *Options) error
+	EndFile(w io.Writer, f *FileDiff, opts *Options) error
+	EndRun(w io.Writer, opts *Options) error
+}
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[OutputFormat]func() Renderer{
+		OutputSideBySide: func() Renderer { return &sideBySideRenderer{} },
+		OutputUnified:    func() Renderer { return &linesRenderer{format: FormatUnified} },
+		OutputContext:    func() Renderer { return &linesRenderer{format: FormatContext} },
+		OutputNormal:     func() Renderer { return &linesRenderer{format: FormatNormal, header: "diff"} },
+		OutputEd:         func() Renderer { return &linesRenderer{format: FormatEd, header: "diff -e"} },
+		OutputJSON:       func() Renderer { return &filesRenderer{format: FormatJSON} },
+		OutputHTML:       func() Renderer { return &filesRenderer{format: FormatHTML} },
+		OutputMarkdown:   func() Renderer { return &filesRenderer{format: FormatMarkdown} },
+		OutputStat:       func() Renderer { return &filesRenderer{format: FormatStat} },
+		OutputWordDiff:   func() Renderer { return &linesRenderer{format: FormatWordDiff, header: "diff"} },
+		OutputJUnit:      func() Renderer { return &filesRenderer{format: FormatJUnit} },
+		OutputGitHub:     func() Renderer { return &eachFileRenderer{format: FormatGitHub} },
+		OutputQuickfix:   func() Renderer { return &eachFileRenderer{format: FormatQuickfix} },
+	}
+)
+
+// RegisterRenderer makes a format available by name to Compare, Diff and the
+// command line. newRenderer is called for each run. Registering an existing
+// name replaces it.
+func RegisterRenderer(name OutputFormat, newRenderer func() Renderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	renderers[name] = newRenderer
+}
+
+// NewRenderer returns a renderer for the named format. The empty name is the
+// default side-by-side format.
+func NewRenderer(name OutputFormat) (Renderer, error) {
+	if name == "" {
+		name = OutputSideBySide
+	}
+	renderersMu.RLock()
+	newRenderer, ok := renderers[name]
+	renderersMu.RUnlock()
+	if !ok {
+		var names []string
+		for _, n := range RendererNames() {
+			names = append(names, string(n))
+		}
+		return nil, fmt.Errorf("unknown output format %q: expected one of %s", name, strings.Join(names, ", "))
+	}
+	return newRenderer(), nil
+}
+
+// RendererNames lists the registered formats in alphabetical order.
+func RendererNames() []OutputFormat {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	names := make([]OutputFormat, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// renderer returns opts.Renderer if set, or else the renderer for
+// opts.Format. With Stat set, the renderer is wrapped to print a summary
+// first.
+func renderer(opts *Options) (Renderer, error) {
+	r := opts.Renderer
+	if r == nil {
+		var err error
+		if r, err = NewRenderer(opts.Format); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Stat && opts.Format != OutputStat {
+		r = &statRenderer{next: r}
+	}
+	return r, nil
+}
+
+// renderFile drives r over a single file.
+func renderFile(w io.Writer, r Renderer, f *FileDiff, opts *Options) error {
+	fileOpts := *opts
+	fileOpts.Labels = Labels{Left: f.Left, Right: f.Right}
+	fileOpts.Timestamps = Timestamps{Left: f.LeftTime, Right: f.RightTime}
+	if err := r.BeginFile(w, f, &fileOpts); err != nil {
+		return err
+	}
+	for _, line := range f.Lines {
+		if err := r.Row(w, line, &fileOpts); err != nil {
+			return err
+		}
+	}
+	return r.EndFile(w, f, &fileOpts)
+}
+
+// typeMismatch is what Diff prints for a file that is a directory on the
+// other side.
+func typeMismatch(f *FileDiff) string {
+	return fmt.Sprintf("File %s is a directory while file %s is a regular file\n", f.Left, f.Right)
+}
+
+// sideBySideRenderer is the default format, rendered by FormatDiff. Files
+// from Diff are introduced by a Diff "a" "b" header, which Apply reads.
+type sideBySideRenderer struct {
+	rows []DiffLine
+}
+
+func (r *sideBySideRenderer) BeginRun(w io.Writer, opts *Options) error { return nil }
+
+func (r *sideBySideRenderer) BeginFile(w io.Writer, f *FileDiff, opts *Options) error {
+	r.rows = r.rows[:0]
+	return nil
+}
+
+func (r *sideBySideRenderer) Row(w io.Writer, line DiffLine, opts *Options) error {
+	r.rows = append(r.rows, line)
+	return nil
+}
+
+func (r *sideBySideRenderer) EndFile(w io.Writer, f *FileDiff, opts *Options) error {
+	var output string
+	switch {
+	case !opts.walk:
+		output = FormatDiff(r.rows, opts)
+	case f.Status == StatusTypeMismatch:
+		output = typeMismatch(f)
+	case opts.Folding && f.Status == StatusUnchanged:
+	default:
+		output = fmt.Sprintf("Diff %q %q\n", f.Left, f.Right) + FormatDiff(r.rows, opts)
+	}
+	_, err := io.WriteString(w, output)
+	return err
+}
+
+func (r *sideBySideRenderer) EndRun(w io.Writer, opts *Options) error { return nil }
+
+// linesRenderer renders each file on its own with a function such as
+// FormatUnified. Files from Diff that differ are introduced by a header line
+// naming them, if set.
+type linesRenderer struct {
+	format func([]DiffLine, *Options) string
+	header string
+	rows   []DiffLine
+}
+
+func (r *linesRenderer) BeginRun(w io.Writer, opts *Options) error { return nil }
+
+func (r *linesRenderer) BeginFile(w io.Writer, f *FileDiff, opts *Options) error {
+	r.rows = r.rows[:0]
+	return nil
+}
+
+func (r *linesRenderer) Row(w io.Writer, line DiffLine, opts *Options) error {
+	r.rows = append(r.rows, line)
+	return nil
+}
+
+func (r *linesRenderer) EndFile(w io.Writer, f *FileDiff, opts *Options) error {
+	if opts.walk && f.Status == StatusTypeMismatch {
+		_, err := io.WriteString(w, typeMismatch(f))
+		return err
+	}
+	output := r.format(r.rows, opts)
+	if opts.walk && r.header != "" && output != "" {
+		output = strings.Join([]string{r.header, f.Left, f.Right}, " ") + "\n" + output
+	}
+	_, err := io.WriteString(w, output)
+	return err
+}
+
+func (r *linesRenderer) EndRun(w io.Writer, opts *Options) error { return nil }
+
+// filesRenderer renders all the files of a run together with a function such
+// as FormatJSON.
+type filesRenderer struct {
+	format func([]*FileDiff, *Options) string
+	files  []*FileDiff
+}
+
+func (r *filesRenderer) BeginRun(w io.Writer, opts *Options) error {
+	r.files = nil
+	return nil
+}
+
+func (r *filesRenderer) BeginFile(w io.Writer, f *FileDiff, opts *Options) error { return nil }
+
+func (r *filesRenderer) Row(w io.Writer, line DiffLine, opts *Options) error { return nil }
+
+func (r *filesRenderer) EndFile(w io.Writer, f *FileDiff, opts *Options) error {
+	r.files = append(r.files, f)
+	return nil
+}
+
+func (r *filesRenderer) EndRun(w io.Writer, opts *Options) error {
+	_, err := io.WriteString(w, r.format(r.files, opts))
+	return err
+}
+
+// eachFileRenderer renders each file as soon as it has been compared with a
+// function such as FormatGitHub.
+type eachFileRenderer struct {
+	format func([]*FileDiff, *Options) string
+}
+
+func (r *eachFileRenderer) BeginRun(w io.Writer, opts *Options) error { return nil }
+
+func (r *eachFileRenderer) BeginFile(w io.Writer, f *FileDiff, opts *Options) error { return nil }
+
+func (r *eachFileRenderer) Row(w io.Writer, line DiffLine, opts *Options) error { return nil }
+
+func (r *eachFileRenderer) EndFile(w io.Writer, f *FileDiff, opts *Options) error {
+	_, err := io.WriteString(w, r.format([]*FileDiff{f}, opts))
+	return err
+}
+
+func (r *eachFileRenderer) EndRun(w io.Writer, opts *Options) error { return nil }
@@ -0,0 +1,204 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// traceRenderer records the calls made to it.
+type traceRenderer struct{}
+
+func (traceRenderer) BeginRun(w io.Writer, opts *Options) error {
+	_, err := fmt.Fprintln(w, "begin run")
+	return err
+}
+
+func (traceRenderer) BeginFile(w io.Writer, f *FileDiff, opts *Options) error {
+	_, err := fmt.Fprintf(w, "begin file %s %s %s\n", filepath.Base(opts.Labels.Left), filepath.Base(opts.Labels.Right), f.Status)
+	return err
+}
+
+func (traceRenderer) Row(w io.Writer, line DiffLine, opts *Options) error {
+	_, err := fmt.Fprintf(w, "row %q %s %q\n", line.Left, line.Type, line.Right)
+	return err
+}
+
+func (traceRenderer) EndFile(w io.Writer, f *FileDiff, opts *Options) error {
+	_, err := fmt.Fprintln(w, "end file")
+	return err
+}
+
+func (traceRenderer) EndRun(w io.Writer, opts *Options) error {
+	_, err := fmt.Fprintln(w, "end run")
+	return err
+}
+
+func init() {
+	RegisterRenderer("trace", func() Renderer { return traceRenderer{} })
+}
+
+func TestCustomRendererCompare(t *testing.T) {
+	got := Compare("a\nb", "a\nc", OutputFormat("trace"), Labels{Left: "old", Right: "new"})
+	expected := `begin run
+begin file old new modified
+row "a" == "a"
+row "b" 1d "c"
+end file
+end run
+`
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestCustomRendererDiff(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"dir1/a.txt": "x",
+		"dir2/a.txt": "x",
+		"dir2/b.txt": "y",
+	} {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := Diff(filepath.Join(dir, "dir1"), filepath.Join(dir, "dir2"), OutputFormat("trace"))
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	expected := `begin run
+begin file a.txt a.txt unchanged
+row "x" == "x"
+end file
+begin file b.txt b.txt added
+row "" 1d "y"
+end file
+end run
+`
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestRendererNames(t *testing.T) {
+	names := map[OutputFormat]bool{}
+	for _, name := range RendererNames() {
+		names[name] = true
+	}
+	for _, name := range []OutputFormat{OutputSideBySide, OutputUnified, OutputContext, OutputNormal, OutputEd, OutputJSON, OutputHTML, OutputMarkdown, "trace"} {
+		if !names[name] {
+			t.Errorf("Expected %q to be registered", name)
+		}
+	}
+	if _, err := NewRenderer("no-such-format"); err == nil || !strings.Contains(err.Error(), `unknown output format "no-such-format"`) {
+		t.Errorf("Expected an unknown format error, got %v", err)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	var sb strings.Builder
+	if err := CompareTo(&sb, "a", "b", OutputFormat("no-such-format")); err == nil {
+		t.Error("Expected CompareTo to fail for an unknown format")
+	}
+	if got := Compare("a", "b", OutputFormat("no-such-format")); got != "" {
+		t.Errorf("Expected no output for an unknown format, got %q", got)
+	}
+	dir := t.TempDir()
+	if _, err := Diff(dir, dir, OutputFormat("no-such-format")); err == nil {
+		t.Error("Expected Diff to fail for an unknown format")
+	}
+}
+
+// walkRenderer records whether each call is Walking.
+type walkRenderer struct {
+	traceRenderer
+	walks []bool
+}
+
+func (r *walkRenderer) BeginRun(w io.Writer, opts *Options) error {
+	r.walks = append(r.walks, opts.Walking())
+	return nil
+}
+
+func (r *walkRenderer) EndFile(w io.Writer, f *FileDiff, opts *Options) error {
+	r.walks = append(r.walks, opts.Walking())
+	return nil
+}
+
+func TestRendererWalk(t *testing.T) {
+	r := &walkRenderer{}
+	Compare("a", "b", r)
+	if fmt.Sprint(r.walks) != "[false false]" {
+		t.Errorf("Expected Walking false for Compare, got %v", r.walks)
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"dir1/a.txt", "dir2/a.txt"} {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r = &walkRenderer{}
+	if _, err := Diff(filepath.Join(dir, "dir1"), filepath.Join(dir, "dir2"), r); err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if fmt.Sprint(r.walks) != "[true true]" {
+		t.Errorf("Expected Walking true for Diff, got %v", r.walks)
+	}
+}
+
+func TestDiffToStreams(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"dir1/a.txt": "x",
+		"dir2/a.txt": "y",
+		"dir1/b.txt": "z",
+		"dir2/b.txt": "z",
+	} {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// By the time b.txt is read, a.txt has been written out.
+	var sb strings.Builder
+	var before string
+	filter := FileFilter(func(path string) bool {
+		if path == "b.txt" {
+			before = sb.String()
+		}
+		return true
+	})
+	err := DiffTo(&sb, filepath.Join(dir, "dir1"), filepath.Join(dir, "dir2"), OutputFormat("trace"), filter)
+	if err != nil {
+		t.Fatalf("DiffTo failed: %v", err)
+	}
+	expected := `begin run
+begin file a.txt a.txt modified
+row "x" 1d "y"
+end file
+`
+	if before != expected {
+		t.Errorf("Expected before b.txt:\n%s\nGot:\n%s", expected, before)
+	}
+	if !strings.HasPrefix(sb.String(), expected) || !strings.HasSuffix(sb.String(), "end run\n") {
+		t.Errorf("Unexpected output:\n%s", sb.String())
+	}
+}
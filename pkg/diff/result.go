@@ -0,0 +1,94 @@
+package diff
+
+import "time"
+
+// FileStatus describes how a pair of compared files differ.
+type FileStatus string
+
+const (
+	StatusUnchanged    FileStatus = "unchanged"
+	StatusModified     FileStatus = "modified"
+	StatusAdded        FileStatus = "added"         // Only the right file exists
+	StatusDeleted      FileStatus = "deleted"       // Only the left file exists
+	StatusTypeMismatch FileStatus = "type-mismatch" // One side is a directory
+)
+
+// FileDiff is the comparison of one pair of files. Compare produces a single
+// FileDiff named by Labels; Diff produces one for every file it walks.
+type FileDiff struct {
+	Left      string
+	Right     string
+	LeftTime  time.Time
+	RightTime time.Time
+	Status    FileStatus
+	Lines     []DiffLine
+	Stats     Stats
+	// Path is the path of the file relative to the directories given to
+	// Diff. It is empty for Compare and when Diff compares two files.
+	Path string
+}
+
+// newFileDiff wraps the result of Compare in a FileDiff.
+func newFileDiff(lines []DiffLine, opts *Options) *FileDiff {
+	return &FileDiff{
+		Left:      opts.Labels.Left,
+		Right:     opts.Labels.Right,
+		LeftTime:  opts.Timestamps.Left,
+		RightTime: opts.Timestamps.Right,
+		Status:    fileStatus(true, true, lines),
+		Lines:     lines,
+		Stats:     ComputeStats(lines),
+	}
+}
+
+func fileStatus(exists1, exists2 bool, lines []DiffLine) FileStatus {
+	switch {
+	case !exists1:
+		return StatusAdded
+	case !exists2:
+		return StatusDeleted
+	}
+	for _, line := range lines {
+		if !line.Type.IsEqual() {
+			return StatusModified
+		}
+	}
+	return StatusUnchanged
+}
+
+// Stats counts the rows of a comparison by kind of change.
+type Stats struct {
+	Added    int // Rows only on the right
+	Removed  int // Rows only on the left
+	Modified int // Rows on both sides that differ
+}
+
+// Total is the number of rows that differ.
+func (s Stats) Total() int {
+	return s.Added + s.Removed + s.Modified
+}
+
+// Add adds the counts of o to s.
+func (s *Stats) Add(o Stats) {
+	s.Added += o.Added
+	s.Removed += o.Removed
+	s.Modified += o.Modified
+}
+
+// ComputeStats counts the added, removed and modified rows in lines. Rows
+// within Tolerance count as equal.
+func ComputeStats(lines []DiffLine) Stats {
+	var s Stats
+	for _, line := range lines {
+		switch {
+		case line.Type.IsEqual():
+		case line.LeftNum == 0 && line.RightNum != 0:
+			s.Added++
+		case line.RightNum == 0 && line.LeftNum != 0:
+			s.Removed++
+		default:
+			s.Modified++
+		}
+	}
+	return s
+}
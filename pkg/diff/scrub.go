@@ -0,0 +1,120 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Scrubber masks volatile values in a line before it is compared. Every match
+// of Pattern is replaced with Replacement, which may refer to submatches using
+// the syntax of regexp.Regexp.ReplaceAllString.
+type Scrubber struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// Scrubbers is an ordered pipeline of Scrubber applied to every line.
+type Scrubbers []Scrubber
+
+// ShowOriginal makes the output show the original, unscrubbed values while
+// still aligning and classifying lines by their scrubbed form.
+type ShowOriginal bool
+
+var (
+	// ScrubTimestamps masks RFC 3339 timestamps.
+	ScrubTimestamps = Scrubber{
+		Pattern:     regexp.MustCompile(`\d{4}-\d{2}-\d{2}[Tt ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:[Zz]|[+-]\d{2}:\d{2})`),
+		Replacement: "<TIMESTAMP>",
+	}
+	// ScrubUUIDs masks UUIDs in their canonical 8-4-4-4-12 form.
+	ScrubUUIDs = Scrubber{
+		Pattern:     regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`),
+		Replacement: "<UUID>",
+	}
+	// ScrubHexHashes masks hex digests of 32 characters or more (MD5, SHA-1,
+	// SHA-256, ...).
+	ScrubHexHashes = Scrubber{
+		Pattern:     regexp.MustCompile(`\b[0-9a-fA-F]{32,128}\b`),
+		Replacement: "<HASH>",
+	}
+	// ScrubPorts masks port numbers on loopback addresses.
+	ScrubPorts = Scrubber{
+		Pattern:     regexp.MustCompile(`(localhost|127\.0\.0\.1|\[::1\]):\d+`),
+		Replacement: "$1:<PORT>",
+	}
+	// ScrubTempDirs masks paths inside the system temporary directory that
+	// start a word, a quoted string or a value after = or :.
+	ScrubTempDirs = Scrubber{
+		Pattern:     tempDirPattern(),
+		Replacement: "${1}<TMPDIR>",
+	}
+)
+
+// DefaultScrubbers contains all of the built-in scrubbers.
+var DefaultScrubbers = Scrubbers{ScrubTimestamps, ScrubUUIDs, ScrubHexHashes, ScrubPorts, ScrubTempDirs}
+
+// NewScrubber compiles pattern into a Scrubber that replaces matches with
+// replacement.
+func NewScrubber(pattern, replacement string) (Scrubber, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Scrubber{}, err
+	}
+	return Scrubber{Pattern: re, Replacement: replacement}, nil
+}
+
+func tempDirPattern() *regexp.Regexp {
+	roots := []string{regexp.QuoteMeta("/tmp")}
+	if dir := filepath.Clean(os.TempDir()); dir != "/tmp" {
+		roots = append(roots, regexp.QuoteMeta(dir))
+	}
+	return regexp.MustCompile(`(^|[\s"'=:])(?:` + strings.Join(roots, "|") + `)[/\\][^\s"'<>:;,)]*`)
+}
+
+// Scrub applies each scrubber in turn to line.
+func (s Scrubbers) Scrub(line string) string {
+	for _, sc := range s {
+		line = sc.Pattern.ReplaceAllString(line, sc.Replacement)
+	}
+	return line
+}
+
+func (s Scrubbers) scrubLines(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = s.Scrub(line)
+	}
+	return out
+}
+
+// restoreOriginals replaces the scrubbed text in diffs with the lines it was
+// produced from, found by their line numbers.
+func restoreOriginals(diffs []DiffLine, a, scrubbedA, b, scrubbedB []string) {
+	for i := range diffs {
+		line := &diffs[i]
+		l, lok := original(line.Left, line.LeftNum, a, scrubbedA)
+		r, rok := original(line.Right, line.RightNum, b, scrubbedB)
+		if lok {
+			line.Left = l
+		}
+		if rok {
+			line.Right = r
+		}
+		if (lok || rok) && line.Type != DiffEqual && line.Left != line.Right {
+			// Ops must describe the text being rendered.
+			line.Ops = getEditScript(line.Left, line.Right)
+		}
+	}
+}
+
+// original returns the line numbered num before it was scrubbed to text. It
+// reports false for a missing line, or if the line does not hold that
+// scrubbed text, as may happen with a LineUpFunc that does not number lines.
+func original(text string, num int, lines, scrubbed []string) (string, bool) {
+	if num <= 0 || num > len(lines) || scrubbed[num-1] != text {
+		return "", false
+	}
+	return lines[num-1], true
+}
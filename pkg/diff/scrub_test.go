@@ -0,0 +1,99 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultScrubbers(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"started at 2024-03-01T12:30:45.123Z ok", "started at <TIMESTAMP> ok"},
+		{"at 2024-03-01 12:30:45+10:00", "at <TIMESTAMP>"},
+		{"id=123e4567-e89b-12d3-a456-426614174000;", "id=<UUID>;"},
+		{"commit da39a3ee5e6b4b0d3255bfef95601890afd80709", "commit <HASH>"},
+		{"listening on 127.0.0.1:43817", "listening on 127.0.0.1:<PORT>"},
+		{"GET http://localhost:8080/x", "GET http://localhost:<PORT>/x"},
+		{"wrote /tmp/TestFoo123/001/out.txt", "wrote <TMPDIR>"},
+		{"/tmp/a and dir=/tmp/b, path=\"/tmp/c\"", "<TMPDIR> and dir=<TMPDIR>, path=\"<TMPDIR>\""},
+		{"cd /tmpfoo/x /var/tmp/y", "cd /tmpfoo/x /var/tmp/y"},
+		{"nothing volatile here", "nothing volatile here"},
+	}
+	for _, tt := range tests {
+		if got := DefaultScrubbers.Scrub(tt.in); got != tt.want {
+			t.Errorf("Scrub(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCompareWithScrubbers(t *testing.T) {
+	a := "request 1\n2024-03-01T12:30:45Z served 200\ndone"
+	b := "request 1\n2024-03-02T08:00:00Z served 200\ndone"
+
+	mock := &testingTRecorder{}
+	output := Compare(a, b, DefaultScrubbers, TestingT(mock))
+	if mock.failed {
+		t.Errorf("Scrubbed lines should not fail the test:\n%s", output)
+	}
+	if !strings.Contains(output, "<TIMESTAMP> served 200 == <TIMESTAMP> served 200") {
+		t.Errorf("Expected scrubbed values in output, got:\n%s", output)
+	}
+
+	output = Compare(a, b, DefaultScrubbers, ShowOriginal(true))
+	if !strings.Contains(output, "2024-03-01T12:30:45Z served 200 == 2024-03-02T08:00:00Z served 200") {
+		t.Errorf("Expected original values in output, got:\n%s", output)
+	}
+}
+
+func TestCustomScrubberShowOriginal(t *testing.T) {
+	pid, err := NewScrubber(`pid=\d+`, "pid=N")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := []string{"pid=1 start", "pid=1 start", "pid=1 stop"}
+	b := []string{"pid=2 start", "pid=3 start", "pid=4 halt"}
+
+	opts := NewOptions(pid, ShowOriginal(true))
+	diffs := align(a, b, opts)
+	if len(diffs) != 3 {
+		t.Fatalf("Expected 3 diff lines, got %d", len(diffs))
+	}
+	for i, d := range diffs {
+		if d.Left != a[i] || d.Right != b[i] {
+			t.Errorf("Line %d: got %q | %q, want %q | %q", i, d.Left, d.Right, a[i], b[i])
+		}
+	}
+	if diffs[0].Type != DiffEqual || diffs[1].Type != DiffEqual {
+		t.Errorf("Scrubbed lines should be equal, got %v and %v", diffs[0].Type, diffs[1].Type)
+	}
+	if diffs[2].Type == DiffEqual {
+		t.Error("stop vs halt should still differ")
+	}
+	for _, op := range diffs[2].Ops {
+		if strings.Contains(op.Content, "N") {
+			t.Errorf("Ops should describe the original text, got %+v", diffs[2].Ops)
+		}
+	}
+}
+
+func TestEmptyScrubberShowOriginal(t *testing.T) {
+	comment, err := NewScrubber(`^#.*`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := []string{"# built 1", "x"}
+	b := []string{"# built 2", "y"}
+	diffs := align(a, b, NewOptions(Scrubbers{comment}, ShowOriginal(true)))
+	if len(diffs) != 2 {
+		t.Fatalf("Expected 2 diff lines, got %d", len(diffs))
+	}
+	for i, d := range diffs {
+		if d.Left != a[i] || d.Right != b[i] {
+			t.Errorf("Line %d: got %q | %q, want %q | %q", i, d.Left, d.Right, a[i], b[i])
+		}
+	}
+	if diffs[0].Type != DiffEqual {
+		t.Errorf("Lines scrubbed to nothing should be equal, got %v", diffs[0].Type)
+	}
+}
@@ -0,0 +1,102 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Layout arranges the two sides of side-by-side output.
+type Layout string
+
+const (
+	LayoutColumns Layout = "columns" // Left and right next to each other (the default)
+	LayoutStacked Layout = "stacked" // The left line above the right line
+	LayoutAuto    Layout = "auto"    // Stacked when Width is too narrow for two columns
+)
+
+// minColumnWidth is the narrowest column LayoutAuto puts side by side.
+const minColumnWidth = 20
+
+// stackedLayout reports whether FormatDiff should stack the sides, given the
+// widths of the widest line on each side and of the other columns.
+func stackedLayout(maxLeft, maxRight, reserved int, opts *Options) bool {
+	switch opts.Layout {
+	case LayoutStacked:
+		return true
+	case LayoutAuto:
+		available := opts.Width - reserved - 2
+		return opts.Width > 0 && maxLeft+maxRight > available && available/2 < minColumnWidth
+	}
+	return false
+}
+
+// formatStacked renders lines with the left line of each changed row above
+// the right one, marked - and + and followed by the row's symbol. Identical
+// rows are printed once. Lines are fitted to Width on their own rows.
+func formatStacked(lines []DiffLine, opts *Options) string {
+	es := newEditScript(lines)
+	gutters := newGutters(lines, opts)
+	symbolWidth := 2
+	for _, line := range lines {
+		symbolWidth = max(symbolWidth, len(line.Type))
+	}
+	prefixWidth := gutters.width() + symbolWidth + 3
+	col := 0
+	if opts.Width > 0 {
+		col = max(opts.Width-prefixWidth, 1)
+	}
+
+	var sb strings.Builder
+	writeLine := func(sign string, line DiffLine, side renderedSide, leftNum, rightNum int) {
+		for i, part := range fitSide(side, col, opts) {
+			prefix := strings.Repeat(" ", prefixWidth)
+			if i == 0 {
+				signText := sign
+				if opts.TermMode {
+					switch sign {
+					case "-":
+						signText = colorize(sign, opts.theme().Deleted)
+					case "+":
+						signText = colorize(sign, opts.theme().Inserted)
+					}
+				}
+				symbol := fmt.Sprintf(" %-*s ", symbolWidth, line.Type)
+				if opts.TermMode {
+					symbol = colorizeSymbol(symbol, line.Type, opts.theme())
+				}
+				prefix = gutters.left.format(leftNum, opts) + gutters.right.format(rightNum, opts) + signText + symbol
+			}
+			if part.text == "" {
+				prefix = strings.TrimRight(prefix, " ")
+			}
+			sb.WriteString(prefix + part.text + "\n")
+			if part.carets != "" {
+				sb.WriteString(strings.Repeat(" ", prefixWidth) + strings.TrimRight(part.carets, " ") + "\n")
+			}
+		}
+	}
+
+	for _, r := range sideBySideRows(lines, opts) {
+		if r.folded > 0 {
+			sb.WriteString(foldMarker(r.folded, opts))
+			continue
+		}
+		line := lines[r.line]
+		if es.isTerminator(line) {
+			continue
+		}
+		leftSpans, rightSpans := lineSpans(line)
+		left := renderSpans(leftSpans, true, opts)
+		if line.Type == DiffEqual {
+			writeLine(" ", line, left, line.LeftNum, line.RightNum)
+			continue
+		}
+		if line.LeftNum > 0 {
+			writeLine("-", line, left, line.LeftNum, 0)
+		}
+		if line.RightNum > 0 {
+			writeLine("+", line, renderSpans(rightSpans, true, opts), 0, line.RightNum)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,65 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatDiffStacked(t *testing.T) {
+	got := Compare("same\nold line\ngone\n", "same\nnew line\n", LayoutStacked, LineNumbers(true), Markers("caret"))
+	expected := strings.Join([]string{
+		"1 1   == same",
+		"2   - 1d old line",
+		"         ^^^",
+		"  2 + 1d new line",
+		"         ^^^",
+		"3   - 1d gone",
+		"         ^^^^",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestFormatDiffStackedTabs(t *testing.T) {
+	got := Compare("\tx", "\ty", LayoutStacked, TabWidth(4))
+	expected := strings.Join([]string{
+		"- 1d     x",
+		"+ 1d     y",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+}
+
+func TestFormatDiffLayoutAuto(t *testing.T) {
+	a := "short\na line that is much too long to share"
+	b := "short\na line that is much too long to split"
+
+	// Wide enough for two columns of 20.
+	got := Compare(a, b, LayoutAuto, Width(46))
+	if strings.HasPrefix(got, "  == ") {
+		t.Errorf("Expected columns, got:\n%s", got)
+	}
+
+	got = Compare(a, b, LayoutAuto, Width(30))
+	expected := strings.Join([]string{
+		"  == short",
+		"- 1d a line that is much too l",
+		"     ong to share",
+		"+ 1d a line that is much too l",
+		"     ong to split",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+
+	// Lines that fit stay in columns however narrow the width.
+	got = Compare("a", "b", LayoutAuto, Width(10))
+	if expected := "a 1d b\n"; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
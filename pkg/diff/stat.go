@@ -0,0 +1,134 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Stat prints a summary of the changed files, as from FormatStat, before the
+// output of the format. The OutputStat format prints the summary alone.
+type Stat bool
+
+// statBarWidth is the longest histogram bar when no Width is set.
+const statBarWidth = 50
+
+// FormatStat summarises the changed files like git diff --stat: the name of
+// each file, its number of changed rows and a histogram bar of + for added,
+// - for removed and ~ for modified rows, followed by the totals. Bars are
+// scaled down to fit opts.Width, or 50 columns without one.
+func FormatStat(files []*FileDiff, opts *Options) string {
+	var changed []*FileDiff
+	var names []string
+	nameWidth, maxTotal := 0, 0
+	var total Stats
+	for _, f := range files {
+		if f.Status == StatusUnchanged {
+			continue
+		}
+		changed = append(changed, f)
+		name := statName(f)
+		names = append(names, name)
+		nameWidth = max(nameWidth, displayWidth(name))
+		maxTotal = max(maxTotal, f.Stats.Total())
+		total.Add(f.Stats)
+	}
+	if len(changed) == 0 {
+		return ""
+	}
+
+	countWidth := len(strconv.Itoa(maxTotal))
+	barWidth := statBarWidth
+	if opts.Width > 0 {
+		// Room is left for " name | count ".
+		barWidth = max(opts.Width-nameWidth-countWidth-5, 10)
+	}
+	scale := func(n int) int {
+		if n == 0 || maxTotal <= barWidth {
+			return n
+		}
+		return max(n*barWidth/maxTotal, 1)
+	}
+
+	theme := opts.theme()
+	bar := func(n int, mark, code string) string {
+		s := strings.Repeat(mark, scale(n))
+		if opts.TermMode && s != "" {
+			s = colorize(s, code)
+		}
+		return s
+	}
+
+	var sb strings.Builder
+	for i, f := range changed {
+		name := names[i] + strings.Repeat(" ", nameWidth-displayWidth(names[i]))
+		if f.Status == StatusTypeMismatch {
+			fmt.Fprintf(&sb, " %s | type mismatch\n", name)
+			continue
+		}
+		fmt.Fprintf(&sb, " %s | %*d %s%s%s\n", name, countWidth, f.Stats.Total(),
+			bar(f.Stats.Added, "+", theme.Inserted),
+			bar(f.Stats.Removed, "-", theme.Deleted),
+			bar(f.Stats.Modified, "~", theme.Modified))
+	}
+	filesChanged := "files"
+	if len(changed) == 1 {
+		filesChanged = "file"
+	}
+	fmt.Fprintf(&sb, " %d %s changed, %d added, %d removed, %d modified\n",
+		len(changed), filesChanged, total.Added, total.Removed, total.Modified)
+	return sb.String()
+}
+
+// statName is the name FormatStat shows for f: its path within the
+// directories given to Diff, or else the name of the file that exists.
+func statName(f *FileDiff) string {
+	switch {
+	case f.Path != "":
+		return filepath.ToSlash(f.Path)
+	case f.Status == StatusDeleted:
+		return f.Left
+	}
+	return f.Right
+}
+
+// statRenderer prints a summary of the run before the output of next, which
+// is held back until the run ends.
+type statRenderer struct {
+	next  Renderer
+	files []*FileDiff
+	buf   strings.Builder
+}
+
+func (r *statRenderer) BeginRun(w io.Writer, opts *Options) error {
+	r.files = nil
+	r.buf.Reset()
+	return r.next.BeginRun(&r.buf, opts)
+}
+
+func (r *statRenderer) BeginFile(w io.Writer, f *FileDiff, opts *Options) error {
+	return r.next.BeginFile(&r.buf, f, opts)
+}
+
+func (r *statRenderer) Row(w io.Writer, line DiffLine, opts *Options) error {
+	return r.next.Row(&r.buf, line, opts)
+}
+
+func (r *statRenderer) EndFile(w io.Writer, f *FileDiff, opts *Options) error {
+	r.files = append(r.files, f)
+	return r.next.EndFile(&r.buf, f, opts)
+}
+
+func (r *statRenderer) EndRun(w io.Writer, opts *Options) error {
+	if err := r.next.EndRun(&r.buf, opts); err != nil {
+		return err
+	}
+	output := FormatStat(r.files, opts)
+	if output != "" && r.buf.Len() > 0 {
+		output += "\n"
+	}
+	_, err := io.WriteString(w, output+r.buf.String())
+	return err
+}
@@ -0,0 +1,110 @@
+package diff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeStatTree(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	var long strings.Builder
+	for i := 1; i <= 40; i++ {
+		fmt.Fprintf(&long, "line %d\n", i)
+	}
+	for name, content := range map[string]string{
+		"dir1/same.txt":    "x\n",
+		"dir2/same.txt":    "x\n",
+		"dir1/mod.txt":     "a\nb\nc\n",
+		"dir2/mod.txt":     "a\nB\nc\nd\n",
+		"dir1/sub/old.txt": "gone\n",
+		"dir2/sub/new.txt": long.String(),
+	} {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestFormatStat(t *testing.T) {
+	dir := writeStatTree(t)
+	got, err := Diff(filepath.Join(dir, "dir1"), filepath.Join(dir, "dir2"), OutputStat)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	expected := strings.Join([]string{
+		" mod.txt     |  2 +~",
+		" sub/new.txt | 40 ++++++++++++++++++++++++++++++++++++++++",
+		" sub/old.txt |  1 -",
+		" 3 files changed, 41 added, 1 removed, 1 modified",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+
+	got, err = Diff(filepath.Join(dir, "dir1"), filepath.Join(dir, "dir2"), OutputStat, Width(30))
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	expected = strings.Join([]string{
+		" mod.txt     |  2 +~",
+		" sub/new.txt | 40 ++++++++++++",
+		" sub/old.txt |  1 -",
+		" 3 files changed, 41 added, 1 removed, 1 modified",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestStatBeforeDiff(t *testing.T) {
+	dir := writeStatTree(t)
+	left, right := filepath.Join(dir, "dir1", "mod.txt"), filepath.Join(dir, "dir2", "mod.txt")
+	got, err := Diff(left, right, OutputNormal, Stat(true))
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	expected := strings.Join([]string{
+		" " + right + " | 2 +~",
+		" 1 file changed, 1 added, 0 removed, 1 modified",
+		"",
+		"diff " + left + " " + right,
+		"2c2",
+		"< b",
+		"---",
+		"> B",
+		"3a4",
+		"> d",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestFormatStatNoChanges(t *testing.T) {
+	if got := Compare("a\nb", "a\nb", OutputStat); got != "" {
+		t.Errorf("Expected no output, got %q", got)
+	}
+	if got := Compare("a\nb", "a\nb", Stat(true), OutputUnified); got != "" {
+		t.Errorf("Expected no output, got %q", got)
+	}
+}
+
+func TestComputeStatsTolerance(t *testing.T) {
+	opts := NewOptions(Tolerance{Abs: 0.01})
+	lines := align([]string{"x 1.000", "y", "z"}, []string{"x 1.001", "y", "w"}, opts)
+	if got, expected := ComputeStats(lines), (Stats{Modified: 1}); got != expected {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+}
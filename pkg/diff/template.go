@@ -0,0 +1,137 @@
+package diff
+
+import (
+	"embed"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// builtinTemplates are the templates available by name to BuiltinTemplate.
+//
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// TemplateData is the value templates are executed with.
+type TemplateData struct {
+	Files []*FileDiff
+	Stats Stats // Totals over all the files
+}
+
+// TemplateFuncs returns the functions available to templates:
+//
+//	color NAME TEXT   TEXT in the Theme's colour for an element (deleted,
+//	                  inserted, header and so on) or a colour for ParseColor
+//	                  (red, bold+#ff8000) in TermMode, unchanged otherwise
+//	pad WIDTH TEXT    TEXT padded with spaces to WIDTH display columns
+//	escape TEXT       TEXT escaped for HTML and XML
+//	coalesce OPS      OPS with adjacent operations of the same type merged
+//	hunks LINES       the Hunks of LINES with opts.ContextLines of context
+//
+// Templates are parsed with these functions by ParseTemplate and executed
+// with the options of the run.
+func TemplateFuncs(opts *Options) template.FuncMap {
+	return template.FuncMap{
+		"color": func(name string, text interface{}) (string, error) {
+			code, err := templateColor(name, opts)
+			if err != nil {
+				return "", err
+			}
+			s := fmt.Sprint(text)
+			if !opts.TermMode || s == "" {
+				return s, nil
+			}
+			return colorize(s, code), nil
+		},
+		"pad": func(width int, text interface{}) string {
+			s := fmt.Sprint(text)
+			if n := width - displayWidth(s); n > 0 {
+				s += strings.Repeat(" ", n)
+			}
+			return s
+		},
+		"escape": func(text interface{}) string {
+			return html.EscapeString(fmt.Sprint(text))
+		},
+		"coalesce": CoalesceOps,
+		"hunks": func(lines []DiffLine) []Hunk {
+			return Hunks(lines, opts.ContextLines)
+		},
+	}
+}
+
+// templateColor resolves the colour name given to the color function.
+func templateColor(name string, opts *Options) (string, error) {
+	theme := opts.theme()
+	if field, ok := theme.fields()[name]; ok {
+		return *field, nil
+	}
+	return ParseColor(name)
+}
+
+// ParseTemplate parses text as an output template with the TemplateFuncs.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(TemplateFuncs(NewOptions())).Parse(text)
+}
+
+// BuiltinTemplate returns one of the templates shipped with the package, by
+// name without the .tmpl extension.
+func BuiltinTemplate(name string) (*template.Template, error) {
+	text, err := builtinTemplates.ReadFile(path.Join("templates", name+".tmpl"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown template %q: expected one of %s", name, strings.Join(BuiltinTemplateNames(), ", "))
+	}
+	return ParseTemplate(name, string(text))
+}
+
+// BuiltinTemplateNames lists the built-in templates in alphabetical order.
+func BuiltinTemplateNames() []string {
+	entries, _ := builtinTemplates.ReadDir("templates")
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".tmpl"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewTemplateRenderer returns a Renderer that executes t once for the run
+// with a TemplateData. Pass it to Compare or Diff as an option to use it in
+// place of the OutputFormat.
+func NewTemplateRenderer(t *template.Template) Renderer {
+	return &templateRenderer{tmpl: t}
+}
+
+type templateRenderer struct {
+	tmpl *template.Template
+	data TemplateData
+}
+
+func (r *templateRenderer) BeginRun(w io.Writer, opts *Options) error {
+	r.data = TemplateData{}
+	return nil
+}
+
+func (r *templateRenderer) BeginFile(w io.Writer, f *FileDiff, opts *Options) error { return nil }
+
+func (r *templateRenderer) Row(w io.Writer, line DiffLine, opts *Options) error { return nil }
+
+func (r *templateRenderer) EndFile(w io.Writer, f *FileDiff, opts *Options) error {
+	r.data.Files = append(r.data.Files, f)
+	r.data.Stats.Add(f.Stats)
+	return nil
+}
+
+func (r *templateRenderer) EndRun(w io.Writer, opts *Options) error {
+	// The functions are rebound to the options of this run on a copy, so the
+	// template can be shared between runs.
+	t, err := r.tmpl.Clone()
+	if err != nil {
+		return err
+	}
+	return t.Funcs(TemplateFuncs(opts)).Execute(w, r.data)
+}
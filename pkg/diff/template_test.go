@@ -0,0 +1,129 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateRenderer(t *testing.T) {
+	tmpl, err := ParseTemplate("test", `{{range .Files}}{{pad 6 .Left}}|{{.Status}}
+{{range .Lines}}{{.Type}} {{escape .Left}}{{range coalesce .Ops}} {{.Type}}:{{.Content}}{{end}}
+{{end}}{{end}}+{{.Stats.Added}} -{{.Stats.Removed}} ~{{.Stats.Modified}}
+`)
+	if err != nil {
+		t.Fatalf("ParseTemplate failed: %v", err)
+	}
+	got := Compare("a\n<b>", "a\n<c>\nd", NewTemplateRenderer(tmpl), Labels{Left: "old", Right: "new"})
+	expected := strings.Join([]string{
+		"old   |modified",
+		"== a",
+		"1d &lt;b&gt; match:< insert:c delete:b match:>",
+		"1d  insert:d",
+		"+1 -0 ~1",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestTemplateColor(t *testing.T) {
+	tmpl, err := ParseTemplate("test", `{{color "red" "x"}} {{pad 3 "日"}}|`)
+	if err != nil {
+		t.Fatalf("ParseTemplate failed: %v", err)
+	}
+	r := NewTemplateRenderer(tmpl)
+	if got, expected := Compare("a", "a", r), "x 日 |"; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	if got, expected := Compare("a", "a", r, TermMode(true)), "\033[31mx\033[0m 日 |"; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestTemplateError(t *testing.T) {
+	tmpl, err := ParseTemplate("test", `{{color "mauve" "x"}}`)
+	if err != nil {
+		t.Fatalf("ParseTemplate failed: %v", err)
+	}
+	if got := Compare("a", "a", NewTemplateRenderer(tmpl)); got != "" {
+		t.Errorf("Expected no output, got %q", got)
+	}
+	mock := &testingTRecorder{}
+	Compare("a", "a", NewTemplateRenderer(tmpl), TestingT(mock))
+	if !mock.failed {
+		t.Error("Expected the rendering error to be reported to TestingT")
+	}
+	var sb strings.Builder
+	if err := CompareTo(&sb, "a", "a", NewTemplateRenderer(tmpl)); err == nil || !strings.Contains(err.Error(), `unknown colour "mauve"`) {
+		t.Errorf("Expected CompareTo to return the rendering error, got %v", err)
+	}
+	if strings.Contains(sb.String(), "Error rendering output") {
+		t.Errorf("Expected no error text in the output of CompareTo, got %q", sb.String())
+	}
+
+	dir := t.TempDir()
+	if _, err := Diff(dir, dir, NewTemplateRenderer(tmpl)); err == nil {
+		t.Error("Expected Diff to return the rendering error")
+	}
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for name, content := range map[string]string{
+		"dir1/same.txt": "x\n",
+		"dir2/same.txt": "x\n",
+		"dir1/mod.txt":  "a\nb\n",
+		"dir2/mod.txt":  "a\nc\n",
+		"dir2/new.txt":  "n\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range []struct {
+		name     string
+		expected string
+	}{
+		{"summary", strings.Join([]string{
+			"modified      dir2/mod.txt  +0 -0 ~1",
+			"added         dir2/new.txt  +1 -0 ~0",
+			"3 file(s): 1 added, 0 removed, 1 modified",
+			"",
+		}, "\n")},
+		{"changes", strings.Join([]string{
+			"dir1/mod.txt:2: - b",
+			"dir2/mod.txt:2: + c",
+			"dir2/new.txt:1: + n",
+			"",
+		}, "\n")},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := BuiltinTemplate(tt.name)
+			if err != nil {
+				t.Fatalf("BuiltinTemplate failed: %v", err)
+			}
+			got, err := Diff("dir1", "dir2", NewTemplateRenderer(tmpl))
+			if err != nil {
+				t.Fatalf("Diff failed: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := BuiltinTemplate("no-such-template"); err == nil {
+		t.Error("Expected an unknown template to fail")
+	}
+	if names := strings.Join(BuiltinTemplateNames(), ","); names != "changes,summary,xml" {
+		t.Errorf("Unexpected built-in templates %s", names)
+	}
+}
@@ -0,0 +1,233 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Theme is the set of colours used in TermMode. Each colour is a list of ANSI
+// SGR parameters, such as "31" for red, "38;5;208" for colour 208 of the
+// 256-colour palette or "38;2;255;128;0" for a truecolor orange, and may set
+// a background too. An empty colour leaves text as it is. ParseTheme builds
+// themes from colour names.
+type Theme struct {
+	Equal        string // Symbol of matching lines
+	Minor        string // Symbol of whitespace, EOL and tolerance differences
+	Changed      string // Symbol of other differences
+	Deleted      string // Deleted lines
+	Inserted     string // Inserted lines
+	Modified     string // Changed lines of context diffs
+	DeletedText  string // Deleted characters within a line
+	InsertedText string // Inserted characters within a line
+	File         string // File headers
+	Header       string // Hunk headers and fold markers
+	LineNumber   string // Line number gutters
+}
+
+// DefaultTheme uses the basic terminal colours and is used when no Theme is
+// set.
+var DefaultTheme = Theme{
+	Equal:        "32",
+	Minor:        "33",
+	Changed:      "31",
+	Deleted:      "31",
+	Inserted:     "32",
+	Modified:     "33",
+	DeletedText:  "31",
+	InsertedText: "32",
+	File:         "1",
+	Header:       "36",
+	LineNumber:   "90",
+}
+
+// themes are the built-in themes selectable by name in ParseTheme. The dark,
+// light and colour-blind themes use the 256-colour palette and highlight
+// changed characters with a background.
+var themes = map[string]Theme{
+	"default": DefaultTheme,
+	"dark": {
+		Equal:        "38;5;71",
+		Minor:        "38;5;179",
+		Changed:      "38;5;167",
+		Deleted:      "38;5;167",
+		Inserted:     "38;5;71",
+		Modified:     "38;5;179",
+		DeletedText:  "38;5;224;48;5;88",
+		InsertedText: "38;5;194;48;5;22",
+		File:         "1",
+		Header:       "38;5;74",
+		LineNumber:   "38;5;243",
+	},
+	"light": {
+		Equal:        "38;5;28",
+		Minor:        "38;5;130",
+		Changed:      "38;5;124",
+		Deleted:      "38;5;124",
+		Inserted:     "38;5;28",
+		Modified:     "38;5;130",
+		DeletedText:  "38;5;124;48;5;224",
+		InsertedText: "38;5;22;48;5;194",
+		File:         "1",
+		Header:       "38;5;25",
+		LineNumber:   "38;5;245",
+	},
+	// Blue and orange stay distinct with the common forms of colour
+	// blindness, where red and green do not.
+	"colorblind": {
+		Equal:        "38;5;33",
+		Minor:        "38;5;141",
+		Changed:      "38;5;208",
+		Deleted:      "38;5;208",
+		Inserted:     "38;5;33",
+		Modified:     "38;5;141",
+		DeletedText:  "30;48;5;208",
+		InsertedText: "97;48;5;33",
+		File:         "1",
+		Header:       "38;5;247",
+		LineNumber:   "38;5;243",
+	},
+}
+
+// ThemeNames lists the built-in themes in alphabetical order.
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseTheme builds a Theme from a comma-separated list of a built-in theme
+// name and element=colour settings, for example "dark,deleted=red+bold" or
+// "inserted-text=black+on-#00cc66". Settings start from DefaultTheme if no
+// theme is named. The elements are equal, minor, changed, deleted, inserted,
+// modified, deleted-text, inserted-text, file, header and line-number; see
+// ParseColor for colours.
+func ParseTheme(spec string) (Theme, error) {
+	theme := DefaultTheme
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			t, ok := themes[name]
+			if !ok {
+				return Theme{}, fmt.Errorf("unknown theme %q: expected one of %s", name, strings.Join(ThemeNames(), ", "))
+			}
+			theme = t
+			continue
+		}
+		field, ok := theme.fields()[strings.TrimSpace(name)]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown theme element %q", name)
+		}
+		code, err := ParseColor(strings.TrimSpace(value))
+		if err != nil {
+			return Theme{}, err
+		}
+		*field = code
+	}
+	return theme, nil
+}
+
+func (t *Theme) fields() map[string]*string {
+	return map[string]*string{
+		"equal":         &t.Equal,
+		"minor":         &t.Minor,
+		"changed":       &t.Changed,
+		"deleted":       &t.Deleted,
+		"inserted":      &t.Inserted,
+		"modified":      &t.Modified,
+		"deleted-text":  &t.DeletedText,
+		"inserted-text": &t.InsertedText,
+		"file":          &t.File,
+		"header":        &t.Header,
+		"line-number":   &t.LineNumber,
+	}
+}
+
+// colorNames are the basic terminal colours, numbered from 30 for the
+// foreground and 40 for the background.
+var colorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// colorAttributes are the text attributes accepted by ParseColor.
+var colorAttributes = map[string]string{
+	"bold":      "1",
+	"dim":       "2",
+	"italic":    "3",
+	"underline": "4",
+	"reverse":   "7",
+}
+
+// ParseColor converts a colour description into SGR parameters for a Theme.
+// A description is one or more terms joined with "+": a basic colour name
+// (black, red, green, yellow, blue, magenta, cyan, white, optionally prefixed
+// with "bright-", or grey), a 256-colour palette number, a truecolor "#rrggbb"
+// or an attribute (bold, dim, italic, underline, reverse). Colours prefixed
+// with "on-" set the background. "none" is no colour.
+func ParseColor(s string) (string, error) {
+	if s == "none" || s == "" {
+		return "", nil
+	}
+	var codes []string
+	for _, term := range strings.Split(s, "+") {
+		if code, ok := colorAttributes[term]; ok {
+			codes = append(codes, code)
+			continue
+		}
+		code, err := parseColorTerm(term)
+		if err != nil {
+			return "", err
+		}
+		codes = append(codes, code)
+	}
+	return strings.Join(codes, ";"), nil
+}
+
+func parseColorTerm(term string) (string, error) {
+	color, base := term, 30
+	if c, ok := strings.CutPrefix(term, "on-"); ok {
+		color, base = c, 40
+	}
+	if color == "grey" || color == "gray" {
+		color = "bright-black"
+	}
+	bright := false
+	if c, ok := strings.CutPrefix(color, "bright-"); ok {
+		color, bright = c, true
+	}
+	for i, name := range colorNames {
+		if name != color {
+			continue
+		}
+		if bright {
+			return strconv.Itoa(base + 60 + i), nil
+		}
+		return strconv.Itoa(base + i), nil
+	}
+	if !bright {
+		if n, err := strconv.Atoi(color); err == nil && n >= 0 && n <= 255 {
+			return fmt.Sprintf("%d;5;%d", base+8, n), nil
+		}
+		if hex, ok := strings.CutPrefix(color, "#"); ok && len(hex) == 6 {
+			if rgb, err := strconv.ParseUint(hex, 16, 32); err == nil {
+				return fmt.Sprintf("%d;2;%d;%d;%d", base+8, rgb>>16, rgb>>8&0xFF, rgb&0xFF), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unknown colour %q", term)
+}
+
+// theme returns the Theme to colour output with, DefaultTheme if none is
+// set. A Theme with no colours set leaves output uncoloured.
+func (o *Options) theme() Theme {
+	if o.Theme == nil {
+		return DefaultTheme
+	}
+	return *o.Theme
+}
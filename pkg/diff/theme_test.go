@@ -0,0 +1,104 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	for _, tt := range []struct {
+		in       string
+		expected string
+	}{
+		{"", ""},
+		{"none", ""},
+		{"red", "31"},
+		{"on-blue", "44"},
+		{"bright-green", "92"},
+		{"on-bright-white", "107"},
+		{"grey", "90"},
+		{"bold+yellow", "1;33"},
+		{"208", "38;5;208"},
+		{"on-22", "48;5;22"},
+		{"#ff8000", "38;2;255;128;0"},
+		{"black+on-#00CC66", "30;48;2;0;204;102"},
+	} {
+		got, err := ParseColor(tt.in)
+		if err != nil {
+			t.Errorf("ParseColor(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseColor(%q) = %q, expected %q", tt.in, got, tt.expected)
+		}
+	}
+	for _, in := range []string{"mauve", "256", "#ff80", "bright-208", "red+"} {
+		if _, err := ParseColor(in); err == nil {
+			t.Errorf("Expected ParseColor(%q) to fail", in)
+		}
+	}
+}
+
+func TestParseTheme(t *testing.T) {
+	theme, err := ParseTheme("")
+	if err != nil || theme != DefaultTheme {
+		t.Errorf("Expected the default theme, got %+v, %v", theme, err)
+	}
+
+	theme, err = ParseTheme("dark, deleted=red+bold,line-number=none")
+	if err != nil {
+		t.Fatalf("ParseTheme failed: %v", err)
+	}
+	expected := themes["dark"]
+	expected.Deleted = "31;1"
+	expected.LineNumber = ""
+	if theme != expected {
+		t.Errorf("Expected %+v, got %+v", expected, theme)
+	}
+
+	for _, spec := range []string{"sepia", "deleted", "removed=red", "deleted=mauve"} {
+		if _, err := ParseTheme(spec); err == nil {
+			t.Errorf("Expected ParseTheme(%q) to fail", spec)
+		}
+	}
+	if names := strings.Join(ThemeNames(), ","); names != "colorblind,dark,default,light" {
+		t.Errorf("Unexpected themes %s", names)
+	}
+}
+
+func TestEmptyThemeOutput(t *testing.T) {
+	theme, err := ParseTheme("equal=none,minor=none,changed=none,deleted=none,inserted=none,modified=none,deleted-text=none,inserted-text=none,file=none,header=none,line-number=none")
+	if err != nil {
+		t.Fatalf("ParseTheme failed: %v", err)
+	}
+	got := Compare("same\nab", "same\nac", TermMode(true), theme)
+	if expected := Compare("same\nab", "same\nac"); got != expected {
+		t.Errorf("Expected no colours:\n%q\nGot:\n%q", expected, got)
+	}
+}
+
+func TestThemeColorsOutput(t *testing.T) {
+	theme := Theme{Equal: "2", Changed: "35", DeletedText: "41", InsertedText: "42"}
+	got := Compare("same\nab", "same\nac", TermMode(true), theme)
+	expected := strings.Join([]string{
+		"same\033[2m == \033[0msame",
+		"a\033[41mb\033[0m  \033[35m 1d \033[0ma\033[42mc\033[0m",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+
+	got = Compare("-a\n", "+a\n", TermMode(true), OutputUnified, Theme{Deleted: "1;31"})
+	expected = strings.Join([]string{
+		"--- a",
+		"+++ b",
+		"@@ -1 +1 @@",
+		"\033[1;31m--a\033[0m",
+		"++a",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+}
@@ -0,0 +1,72 @@
+package diff
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+)
+
+// Tolerance makes lines that differ only in their numbers compare as matching
+// when every number is within Abs of its counterpart, or within Rel of the
+// larger magnitude of the two. Such lines are reported as DiffTolerance.
+type Tolerance struct {
+	Abs float64
+	Rel float64
+}
+
+var numberPattern = regexp.MustCompile(`[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`)
+
+// Within reports whether a and b are within the tolerance of each other.
+func (t Tolerance) Within(a, b float64) bool {
+	if a == b {
+		return true
+	}
+	d := math.Abs(a - b)
+	if d <= t.Abs {
+		return true
+	}
+	return d <= t.Rel*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// LinesWithin reports whether left and right have identical text outside of
+// their numeric tokens and each pair of numbers is within the tolerance.
+func (t Tolerance) LinesWithin(left, right string) bool {
+	li := numberPattern.FindAllStringIndex(left, -1)
+	ri := numberPattern.FindAllStringIndex(right, -1)
+	if len(li) != len(ri) || len(li) == 0 {
+		return false
+	}
+	lp, rp := 0, 0
+	for k := range li {
+		if left[lp:li[k][0]] != right[rp:ri[k][0]] {
+			return false
+		}
+		a, err := strconv.ParseFloat(left[li[k][0]:li[k][1]], 64)
+		if err != nil {
+			return false
+		}
+		b, err := strconv.ParseFloat(right[ri[k][0]:ri[k][1]], 64)
+		if err != nil {
+			return false
+		}
+		if !t.Within(a, b) {
+			return false
+		}
+		lp, rp = li[k][1], ri[k][1]
+	}
+	return left[lp:] == right[rp:]
+}
+
+// toleranceClassifier reports numeric near-misses as DiffTolerance and defers
+// everything else to next.
+type toleranceClassifier struct {
+	tolerance Tolerance
+	next      Classifier
+}
+
+func (c toleranceClassifier) Classify(left, right string, ops []Operation) Classification {
+	if left != right && c.tolerance.LinesWithin(left, right) {
+		return Classification{Type: DiffTolerance}
+	}
+	return c.next.Classify(left, right, ops)
+}
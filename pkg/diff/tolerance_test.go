@@ -0,0 +1,85 @@
+package diff
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestToleranceLinesWithin(t *testing.T) {
+	tol := Tolerance{Abs: 1e-6, Rel: 1e-9}
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"x=1.0000001 y=2", "x=1.0000002 y=2", true},
+		{"x=1.0001 y=2", "x=1.0002 y=2", false},
+		{"x=1.0000001 y=2", "z=1.0000002 y=2", false},
+		{"t=3.0e10", "t=3.00000000001e10", true},
+		{"1 2", "1 2 3", false},
+		{"no numbers", "no numbers!", false},
+	}
+	for _, tt := range tests {
+		if got := tol.LinesWithin(tt.a, tt.b); got != tt.want {
+			t.Errorf("LinesWithin(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestToleranceRelative(t *testing.T) {
+	tol := Tolerance{Rel: 0.01}
+	if !tol.Within(1000, 1005) {
+		t.Error("1000 and 1005 should be within 1% of each other")
+	}
+	if tol.Within(1, 1.05) {
+		t.Error("1 and 1.05 should not be within 1% of each other")
+	}
+}
+
+func TestCompareWithTolerance(t *testing.T) {
+	a := "step 1\nenergy 0.3333333333\nstep 2"
+	b := "step 1\nenergy 0.3333333334\nstep 2"
+
+	mock := &testingTRecorder{}
+	output := Compare(a, b, Tolerance{Abs: 1e-9}, TestingT(mock))
+	if mock.failed {
+		t.Errorf("Lines within tolerance should not fail the test:\n%s", output)
+	}
+	expected := "step 1              == step 1\nenergy 0.3333333333 ~  energy 0.3333333334\nstep 2              == step 2\n"
+	if output != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, output)
+	}
+
+	mock = &testingTRecorder{}
+	Compare(a, b, TestingT(mock))
+	if !mock.failed {
+		t.Error("Without tolerance the numeric difference should fail the test")
+	}
+}
+
+func TestToleranceFileStatus(t *testing.T) {
+	a, b := "x 1.000\ny\n", "x 1.001\ny\n"
+	var doc JSONDocument
+	if err := json.Unmarshal([]byte(Compare(a, b, Tolerance{Abs: 0.01}, OutputJSON)), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Files[0].Status; got != StatusUnchanged {
+		t.Errorf("Expected status %q within tolerance, got %q", StatusUnchanged, got)
+	}
+
+	if err := json.Unmarshal([]byte(Compare(a, b, OutputJSON)), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Files[0].Status; got != StatusModified {
+		t.Errorf("Expected status %q without tolerance, got %q", StatusModified, got)
+	}
+}
+
+type testingTRecorder struct {
+	failed bool
+}
+
+func (r *testingTRecorder) Helper() {}
+
+func (r *testingTRecorder) Errorf(format string, args ...interface{}) {
+	r.failed = true
+}
@@ -119,6 +119,14 @@ func TestTxtar(t *testing.T) {
 						if b, ok := v.(bool); ok {
 							opts = append(opts, Interactive(b))
 						}
+					case "ShowInvisibles":
+						if b, ok := v.(bool); ok {
+							opts = append(opts, ShowInvisibles(b))
+						}
+					case "Markers":
+						if s, ok := v.(string); ok {
+							opts = append(opts, Markers(s))
+						}
 					}
 				}
 			}
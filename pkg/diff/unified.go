@@ -0,0 +1,93 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timestampLayout is the timestamp format GNU diff uses in file headers.
+const timestampLayout = "2006-01-02 15:04:05.000000000 -0700"
+
+const noEOLMarker = `\ No newline at end of file`
+
+// FormatUnified renders lines as a unified diff (diff -u) that patch and
+// git apply accept. Files are named by opts.Labels and opts.Timestamps, and
+// each hunk has opts.ContextLines lines of context. Identical inputs produce
+// no output.
+func FormatUnified(lines []DiffLine, opts *Options) string {
+	es := newEditScript(lines)
+	hunks := es.hunks(opts.ContextLines)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(styleLine("--- "+fileHeader(opts.Labels.Left, opts.Timestamps.Left), opts.theme().File, opts))
+	sb.WriteString(styleLine("+++ "+fileHeader(opts.Labels.Right, opts.Timestamps.Right), opts.theme().File, opts))
+	for _, line := range unifiedHunkLines(es, hunks) {
+		switch line[0] {
+		case '@':
+			sb.WriteString(styleLine(line, opts.theme().Header, opts))
+		case '-':
+			sb.WriteString(styleLine(line, opts.theme().Deleted, opts))
+		case '+':
+			sb.WriteString(styleLine(line, opts.theme().Inserted, opts))
+		default:
+			sb.WriteString(line + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// unifiedHunkLines returns the lines of hunks in unified format, without
+// the file headers or line terminators.
+func unifiedHunkLines(es *editScript, hunks []editHunk) []string {
+	var lines []string
+	for _, h := range hunks {
+		lines = append(lines, fmt.Sprintf("@@ -%s +%s @@", unifiedRange(h.leftStart, h.leftCount), unifiedRange(h.rightStart, h.rightCount)))
+		for _, e := range h.edits {
+			switch e.kind {
+			case editEqual:
+				lines = append(lines, " "+e.text)
+			case editDelete:
+				lines = append(lines, "-"+e.text)
+			case editInsert:
+				lines = append(lines, "+"+e.text)
+			}
+			if es.missingEOL(e) {
+				lines = append(lines, noEOLMarker)
+			}
+		}
+	}
+	return lines
+}
+
+// fileHeader formats a label and optional timestamp for a diff file header.
+func fileHeader(label string, t time.Time) string {
+	if t.IsZero() {
+		return label
+	}
+	return label + "\t" + t.Format(timestampLayout)
+}
+
+// unifiedRange formats a hunk range as "start,count", where start is the first
+// line of the range, or the line before it if the range is empty.
+func unifiedRange(before, count int) string {
+	start := before + 1
+	if count == 0 {
+		start = before
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// styleLine terminates s with a newline, colouring it in TermMode.
+func styleLine(s, code string, opts *Options) string {
+	if opts.TermMode {
+		s = colorize(s, code)
+	}
+	return s + "\n"
+}
@@ -0,0 +1,188 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		context  int
+		expected string
+	}{
+		{
+			name:     "identical",
+			a:        "a\nb\n",
+			b:        "a\nb\n",
+			context:  3,
+			expected: "",
+		},
+		{
+			name:    "modified line",
+			a:       "one\ntwo\nthree\n",
+			b:       "one\nTWO\nthree\n",
+			context: 3,
+			expected: `--- a
++++ b
+@@ -1,3 +1,3 @@
+ one
+-two
++TWO
+ three
+`,
+		},
+		{
+			name:    "separate hunks",
+			a:       "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:       "1\nX\n3\n4\n5\n6\n7\nY\n9\n",
+			context: 1,
+			expected: `--- a
++++ b
+@@ -1,3 +1,3 @@
+ 1
+-2
++X
+ 3
+@@ -7,3 +7,3 @@
+ 7
+-8
++Y
+ 9
+`,
+		},
+		{
+			name:    "no newline at end of file",
+			a:       "x\ny",
+			b:       "x\ny\nz\n",
+			context: 3,
+			expected: `--- a
++++ b
+@@ -1,2 +1,3 @@
+ x
+-y
+\ No newline at end of file
++y
++z
+`,
+		},
+		{
+			name:    "new file",
+			a:       "",
+			b:       "hello\n",
+			context: 3,
+			expected: `--- a
++++ b
+@@ -0,0 +1 @@
++hello
+`,
+		},
+		{
+			name:    "zero context",
+			a:       "a\nb\nc\n",
+			b:       "a\nc\n",
+			context: 0,
+			expected: `--- a
++++ b
+@@ -2 +1,0 @@
+-b
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(tt.a, tt.b, OutputUnified, ContextLines(tt.context))
+			if got != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatUnifiedGroupsChanges(t *testing.T) {
+	// Modified rows from the side-by-side alignment are regrouped so that all
+	// removals come before the additions, as diff -u prints them.
+	got := Compare("a\nb\nc\nd\n", "a\nB\nC\nd\n", OutputUnified, Labels{Left: "old", Right: "new"})
+	expected := `--- old
++++ new
+@@ -1,4 +1,4 @@
+ a
+-b
+-c
++B
++C
+ d
+`
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestFormatUnifiedTolerance(t *testing.T) {
+	// Lines within tolerance are context, shown with their right-hand text.
+	got := Compare("x 1.000\ny\nz\n", "x 1.001\ny\nw\n", OutputUnified, Tolerance{Abs: 0.01}, ContextLines(1), Labels{Left: "old", Right: "new"})
+	expected := `--- old
++++ new
+@@ -2,2 +2,2 @@
+ y
+-z
++w
+`
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+	if got := Compare("x 1.000\n", "x 1.001\n", OutputUnified, Tolerance{Abs: 0.01}); got != "" {
+		t.Errorf("Expected no output within tolerance, got %q", got)
+	}
+}
+
+func TestDiffUnified(t *testing.T) {
+	dir := t.TempDir()
+	dir1 := filepath.Join(dir, "dir1")
+	dir2 := filepath.Join(dir, "dir2")
+	mtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	files := map[string]string{
+		"dir1/same.txt":    "same\n",
+		"dir2/same.txt":    "same\n",
+		"dir1/changed.txt": "old\n",
+		"dir2/changed.txt": "new\n",
+		"dir2/added.txt":   "added\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(p, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := Diff(dir1, dir2, OutputUnified)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	stamp := mtime.Local().Format(timestampLayout)
+	expected := strings.Join([]string{
+		"--- " + filepath.Join(dir1, "added.txt") + "\t1970-01-01 00:00:00.000000000 +0000",
+		"+++ " + filepath.Join(dir2, "added.txt") + "\t" + stamp,
+		"@@ -0,0 +1 @@",
+		"+added",
+		"--- " + filepath.Join(dir1, "changed.txt") + "\t" + stamp,
+		"+++ " + filepath.Join(dir2, "changed.txt") + "\t" + stamp,
+		"@@ -1 +1 @@",
+		"-old",
+		"+new",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
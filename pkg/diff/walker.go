@@ -1,14 +1,27 @@
 package diff
 
 import (
-	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 )
 
 func Diff(path1, path2 string, options ...interface{}) (string, error) {
+	var sb strings.Builder
+	if err := DiffTo(&sb, path1, path2, options...); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// DiffTo is Diff writing its output to w as each file is compared rather
+// than returning it. Formats that summarise the whole run, such as JSON,
+// Markdown, templates and Stat, still write their output at the end. On
+// error, the output of the files before it has already been written.
+func DiffTo(w io.Writer, path1, path2 string, options ...interface{}) error {
 	opts := NewOptions(options...)
 
 	// Initial check to handle file vs dir at root level
@@ -17,16 +30,32 @@ func Diff(path1, path2 string, options ...interface{}) (string, error) {
 
 	// If errors are not IsNotExist, return them
 	if err1 != nil && !os.IsNotExist(err1) {
-		return "", err1
+		return err1
 	}
 	if err2 != nil && !os.IsNotExist(err2) {
-		return "", err2
+		return err2
 	}
 
-	return walk("", path1, path2, opts)
+	opts.walk = true
+	r, err := renderer(opts)
+	if err != nil {
+		return err
+	}
+	if err := r.BeginRun(w, opts); err != nil {
+		return err
+	}
+	err = walk("", path1, path2, opts, func(f *FileDiff) error {
+		return renderFile(w, r, f, opts)
+	})
+	if err != nil {
+		return err
+	}
+	return r.EndRun(w, opts)
 }
 
-func walk(relPath, root1, root2 string, opts *Options) (string, error) {
+// walk visits every pair of files under root1 and root2 in sorted order,
+// calling visit with the comparison of each.
+func walk(relPath, root1, root2 string, opts *Options, visit func(*FileDiff) error) error {
 	path1 := filepath.Join(root1, relPath)
 	path2 := filepath.Join(root2, relPath)
 
@@ -37,7 +66,7 @@ func walk(relPath, root1, root2 string, opts *Options) (string, error) {
 	exists2 := err2 == nil
 
 	if !exists1 && !exists2 {
-		return "", nil
+		return nil
 	}
 
 	// Determine if directories
@@ -46,7 +75,7 @@ func walk(relPath, root1, root2 string, opts *Options) (string, error) {
 
 	// Handle type mismatch (File vs Dir)
 	if exists1 && exists2 && isDir1 != isDir2 {
-		return fmt.Sprintf("File %s is a directory while file %s is a regular file\n", path1, path2), nil
+		return visit(&FileDiff{Left: path1, Right: path2, Status: StatusTypeMismatch, Path: relPath})
 	}
 
 	isDir := isDir1 || isDir2
@@ -64,7 +93,7 @@ func walk(relPath, root1, root2 string, opts *Options) (string, error) {
 		checkPath := relPath
 
 		if checkPath != "" && opts.FileFilter != nil && !opts.FileFilter(checkPath) {
-			return "", nil
+			return nil
 		}
 
 		c1 := ""
@@ -72,14 +101,14 @@ func walk(relPath, root1, root2 string, opts *Options) (string, error) {
 		if exists1 {
 			b, err := os.ReadFile(path1)
 			if err != nil {
-				return "", err
+				return err
 			}
 			c1 = string(b)
 		}
 		if exists2 {
 			b, err := os.ReadFile(path2)
 			if err != nil {
-				return "", err
+				return err
 			}
 			c2 = string(b)
 		}
@@ -87,11 +116,18 @@ func walk(relPath, root1, root2 string, opts *Options) (string, error) {
 		// reuse Compare logic
 		lines1 := strings.Split(c1, "\n")
 		lines2 := strings.Split(c2, "\n")
-		diffs := AlignLines(lines1, lines2, opts)
-		output := FormatDiff(diffs, opts)
-
-		header := fmt.Sprintf("Diff %q %q\n", path1, path2)
-		return header + output, nil
+		diffs := align(lines1, lines2, opts)
+
+		return visit(&FileDiff{
+			Left:      path1,
+			Right:     path2,
+			LeftTime:  modTime(fi1),
+			RightTime: modTime(fi2),
+			Status:    fileStatus(exists1, exists2, diffs),
+			Lines:     diffs,
+			Stats:     ComputeStats(diffs),
+			Path:      relPath,
+		})
 	}
 
 	// Directory recursion
@@ -99,7 +135,7 @@ func walk(relPath, root1, root2 string, opts *Options) (string, error) {
 	if exists1 {
 		des, err := os.ReadDir(path1)
 		if err != nil {
-			return "", err
+			return err
 		}
 		for _, de := range des {
 			entries[de.Name()] = struct{}{}
@@ -108,7 +144,7 @@ func walk(relPath, root1, root2 string, opts *Options) (string, error) {
 	if exists2 {
 		des, err := os.ReadDir(path2)
 		if err != nil {
-			return "", err
+			return err
 		}
 		for _, de := range des {
 			entries[de.Name()] = struct{}{}
@@ -121,15 +157,22 @@ func walk(relPath, root1, root2 string, opts *Options) (string, error) {
 	}
 	sort.Strings(names)
 
-	var sb strings.Builder
 	for _, name := range names {
 		childRel := filepath.Join(relPath, name)
-		res, err := walk(childRel, root1, root2, opts)
-		if err != nil {
-			return "", err
+		if err := walk(childRel, root1, root2, opts, visit); err != nil {
+			return err
 		}
-		sb.WriteString(res)
 	}
 
-	return sb.String(), nil
+	return nil
+}
+
+// modTime returns the modification time of fi, or the Unix epoch for a file
+// that does not exist, which is how diff and patch mark created and deleted
+// files.
+func modTime(fi os.FileInfo) time.Time {
+	if fi == nil {
+		return time.Unix(0, 0).UTC()
+	}
+	return fi.ModTime()
 }
@@ -0,0 +1,96 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"plain", 5},
+		{"café", 4},
+		{"cafe\u0301", 4}, // Combining acute accent
+		{"日本語", 6},
+		{"ｱｲｳ", 3}, // Halfwidth katakana
+		{"ＡＢ", 4},  // Fullwidth Latin
+		{"🎉!", 3},
+		{"zero\u200bwidth", 9},
+		{"\x00\x1b", 0},
+	}
+	for _, tt := range tests {
+		if got := displayWidth(tt.in); got != tt.want {
+			t.Errorf("displayWidth(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExpandTabs(t *testing.T) {
+	tests := []struct {
+		in       string
+		col, tab int
+		want     string
+	}{
+		{"a\tb", 0, 8, "a       b"},
+		{"a\tb", 0, 4, "a   b"},
+		{"\t", 2, 4, "  "},
+		{"日\tx", 0, 4, "日  x"},
+		{"a\tb", 0, 0, "a       b"},
+	}
+	for _, tt := range tests {
+		if got := expandTabs(tt.in, tt.col, tt.tab); got != tt.want {
+			t.Errorf("expandTabs(%q, %d, %d) = %q, want %q", tt.in, tt.col, tt.tab, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDiffDisplayWidth(t *testing.T) {
+	got := Compare("日本\ncafé\nx\ty\n", "日本\ncafe\nx\ty\n", TabWidth(4))
+	expected := strings.Join([]string{
+		"日本  == 日本",
+		"café  1d cafe",
+		"x   y == x\ty",
+		"      ==",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestApplyDisplayWidth(t *testing.T) {
+	dir := t.TempDir()
+	dir1 := filepath.Join(dir, "dir1")
+	dir2 := filepath.Join(dir, "dir2")
+	before := "日本語\ncafé\n\tindented\nplain\n"
+	after := "日本語!\ncafe\n\tindented\nplain\n🎉\n"
+	for name, content := range map[string]string{"dir1/f.txt": before, "dir2/f.txt": after} {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	patch, err := Diff(dir1, dir2)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	// Apply resolves the paths in the Diff header relative to the target.
+	if err := Apply(patch, "/"); err != nil {
+		t.Fatalf("Apply failed: %v\n%s", err, patch)
+	}
+	got, err := os.ReadFile(filepath.Join(dir1, "f.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != after {
+		t.Errorf("Expected %q, got %q\n%s", after, got, patch)
+	}
+}
@@ -0,0 +1,118 @@
+package diff
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FormatWordDiff renders lines in a single column like git diff --word-diff:
+// each line is shown once, with deleted and inserted text inline, coloured in
+// TermMode and otherwise marked as [-deleted-] and {+inserted+}. Folding,
+// LineNumbers and ShowInvisibles apply as in side-by-side output. Identical
+// inputs produce no output.
+func FormatWordDiff(lines []DiffLine, opts *Options) string {
+	if len(Hunks(lines, 0)) == 0 {
+		return ""
+	}
+	lineOpts := *opts
+	switch {
+	case !opts.TermMode:
+		lineOpts.Markers = MarkersInline
+	case opts.Markers != MarkersInline:
+		lineOpts.Markers = MarkersNone
+	}
+
+	es := newEditScript(lines)
+	gutters := newGutters(lines, opts)
+	var sb strings.Builder
+	for _, r := range sideBySideRows(lines, opts) {
+		if r.folded > 0 {
+			sb.WriteString(foldMarker(r.folded, opts))
+			continue
+		}
+		line := lines[r.line]
+		if es.isTerminator(line) {
+			continue
+		}
+		sb.WriteString(gutters.left.format(line.LeftNum, opts))
+		sb.WriteString(gutters.right.format(line.RightNum, opts))
+		sb.WriteString(renderSpans(wordSpans(line), false, &lineOpts).text)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// wordSpans lists the text of line in a single column, with the deleted and
+// inserted parts of changed lines marked. Changes are widened to whole words,
+// so that a partly changed word is shown deleted and inserted in full, and
+// between two unchanged parts the deleted text comes first.
+func wordSpans(line DiffLine) []span {
+	switch {
+	case line.Type == DiffEqual:
+		return []span{{text: line.Left}}
+	case line.RightNum == 0:
+		return []span{{text: line.Left, op: OpDelete}}
+	case line.LeftNum == 0:
+		return []span{{text: line.Right, op: OpInsert}}
+	case len(line.Ops) == 0:
+		return []span{{text: line.Left, op: OpDelete}, {text: line.Right, op: OpInsert}}
+	}
+	ops := CoalesceOps(line.Ops)
+	var spans []span
+	var deleted, inserted strings.Builder
+	flush := func() {
+		if deleted.Len() > 0 {
+			spans = append(spans, span{text: deleted.String(), op: OpDelete})
+		}
+		if inserted.Len() > 0 {
+			spans = append(spans, span{text: inserted.String(), op: OpInsert})
+		}
+		deleted.Reset()
+		inserted.Reset()
+	}
+	for i, op := range ops {
+		switch op.Type {
+		case OpDelete:
+			deleted.WriteString(op.Content)
+			continue
+		case OpInsert:
+			inserted.WriteString(op.Content)
+			continue
+		}
+		text := op.Content
+		if deleted.Len() > 0 || inserted.Len() > 0 {
+			// The rest of a word the change ends in is changed too.
+			end := strings.IndexFunc(text, func(r rune) bool { return !isWordRune(r) })
+			if end < 0 {
+				end = len(text)
+			}
+			deleted.WriteString(text[:end])
+			inserted.WriteString(text[:end])
+			if text = text[end:]; text == "" {
+				continue
+			}
+			flush()
+		}
+		// So is the start of a word the next change begins in.
+		start := len(text)
+		if i+1 < len(ops) {
+			start = 0
+			if k := strings.LastIndexFunc(text, func(r rune) bool { return !isWordRune(r) }); k >= 0 {
+				_, size := utf8.DecodeRuneInString(text[k:])
+				start = k + size
+			}
+		}
+		if start > 0 {
+			spans = append(spans, span{text: text[:start]})
+		}
+		deleted.WriteString(text[start:])
+		inserted.WriteString(text[start:])
+	}
+	flush()
+	return mergeSpans(spans)
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,58 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFormatWordDiff(t *testing.T) {
+	got := Compare("keep\nthe cat sat\ngone\n", "keep\nthe dog sat\nnew\nadded\n", OutputWordDiff)
+	expected := strings.Join([]string{
+		"keep",
+		"the [-cat-]{+dog+} sat",
+		"[-gone-]{+new+}",
+		"{+added+}",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+
+	got = Compare("the cat\n", "the dog\n", OutputWordDiff, TermMode(true), LineNumbers(true))
+	expected = "\033[90m1\033[0m \033[90m1\033[0m the \033[31mcat\033[0m\033[32mdog\033[0m\n"
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	got = Compare("x := foo_bar(naïve)", "x := foo_baz(naive)", OutputWordDiff)
+	expected = "x := [-foo_bar-]{+foo_baz+}([-naïve-]{+naive+})\n"
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	if got := Compare("same\n", "same\n", OutputWordDiff); got != "" {
+		t.Errorf("Expected no output for identical inputs, got %q", got)
+	}
+}
+
+func TestFormatWordDiffFolding(t *testing.T) {
+	var a, b []string
+	for i := 1; i <= 10; i++ {
+		a = append(a, fmt.Sprintf("line %d", i))
+		b = append(b, fmt.Sprintf("line %d", i))
+	}
+	b[4] = "line five"
+	got := Compare(strings.Join(a, "\n"), strings.Join(b, "\n"), OutputWordDiff, ContextLines(1), Folding(true))
+	expected := strings.Join([]string{
+		"··· 3 identical lines ···",
+		"line 4",
+		"line [-5-]{+five+}",
+		"line 6",
+		"··· 4 identical lines ···",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
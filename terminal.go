@@ -0,0 +1,58 @@
+package app
+
+import (
+	"os"
+	"strconv"
+)
+
+// terminalWidth returns the width of the terminal standard output is
+// connected to, or 0 if it is not a terminal. The COLUMNS environment
+// variable overrides the size reported by the terminal.
+func terminalWidth() int {
+	if !stdoutIsTerminal() {
+		return 0
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return fileWidth(os.Stdout)
+}
+
+// outputWidth resolves the --width flag: 0 means no limit and a negative
+// width fits the terminal.
+func outputWidth(width int) int {
+	if width < 0 {
+		return terminalWidth()
+	}
+	return width
+}
+
+// stdoutIsTerminal reports whether standard output is a terminal.
+func stdoutIsTerminal() bool {
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// useColor resolves the --color flag together with --term, which colours
+// unless the mode is never. Without a mode, only --term colours. In auto
+// mode, a non-empty NO_COLOR turns colour off and FORCE_COLOR turns it on
+// (unless it is 0), before falling back to whether standard output is a
+// terminal.
+func useColor(mode string, term bool) bool {
+	switch {
+	case mode == "always":
+		return true
+	case mode == "never":
+		return false
+	case term:
+		return true
+	case mode == "":
+		return false
+	case os.Getenv("NO_COLOR") != "":
+		return false
+	}
+	if force, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		return force != "0"
+	}
+	return stdoutIsTerminal()
+}
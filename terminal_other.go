@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !windows
+
+package app
+
+import "os"
+
+// fileWidth cannot query the terminal size on this platform, so only COLUMNS
+// is used.
+func fileWidth(f *os.File) int {
+	return 0
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package app
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var procGetConsoleScreenBufferInfo = syscall.NewLazyDLL("kernel32.dll").NewProc("GetConsoleScreenBufferInfo")
+
+type consoleScreenBufferInfo struct {
+	size              [2]int16
+	cursorPosition    [2]int16
+	attributes        uint16
+	window            [4]int16 // left, top, right, bottom
+	maximumWindowSize [2]int16
+}
+
+// fileWidth returns the number of columns of the console f refers to, or 0.
+func fileWidth(f *os.File) int {
+	var info consoleScreenBufferInfo
+	if r, _, _ := procGetConsoleScreenBufferInfo.Call(f.Fd(), uintptr(unsafe.Pointer(&info))); r == 0 {
+		return 0
+	}
+	return int(info.window[2] - info.window[0] + 1)
+}